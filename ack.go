@@ -0,0 +1,123 @@
+package slacker
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// ackHash is the database key of the acknowledgement of key, a MessageTag or a message id,
+// its record Time is when it was acknowledged
+func ackHash(key string) string {
+	return "ack:" + key
+}
+
+// acknowledgement holds who acknowledged the alert of a tag and when
+type acknowledgement struct {
+	by string
+	at time.Time
+}
+
+// annotate appends who acknowledged the alert to message
+func (ack acknowledgement) annotate(message string) string {
+	if ack.by == "" {
+		return message
+	}
+
+	return fmt.Sprintf("%s (acked by %s at %s)", message, ack.by, ack.at.Format("15:04"))
+}
+
+// Acknowledge records that who took care of the alert of key, e.g. from automation or a chat command.
+// Key is a MessageTag, acknowledging its messages until Resolve, or the id of a sent message,
+// acknowledging messages of its tag within the same Frequency window.
+// Messages of an acknowledged alert tell who acknowledged it with Acknowledgements set,
+// and are not escalated: no Twilio SMS, CheckVolume anomaly or AnnounceStartup crash loop alerts are sent.
+func (slacker Slacker) Acknowledge(key string, who string) error {
+	if err := slacker.setDefaults(); err != nil {
+		return fmt.Errorf("Slacker failed to acknowledge alert: %s", err)
+	}
+
+	slacker.ctx = context.Background()
+
+	now := time.Now()
+	hash := ackHash(key)
+	err := slacker.updateCounter(hash, func(record *dbRecord) *dbRecord {
+		return &dbRecord{State: recordConfirmed, Time: now, AckedBy: who}
+	})
+	if err != nil {
+		return fmt.Errorf("Slacker failed to acknowledge alert: %s", err)
+	}
+
+	slacker.emit(EventAcknowledge, hash, who, nil)
+	return nil
+}
+
+// ackHashes returns the database keys of acknowledgements of the alert of the tag:
+// of MessageTag and of the id of its message within the current window, if it was sent
+func (slacker Slacker) ackHashes() ([]string, error) {
+	hashes := []string{ackHash(slacker.MessageTag)}
+	if slacker.isAlways() {
+		return hashes, nil
+	}
+
+	record, found, err := slacker.getRecord(slacker.getHash())
+	if err != nil {
+		return nil, err
+	}
+	if found && record.MessageId != "" {
+		hashes = append(hashes, ackHash(record.MessageId))
+	}
+
+	return hashes, nil
+}
+
+// acknowledgement returns who acknowledged the alert of the tag, if anybody did
+func (slacker Slacker) acknowledgement() acknowledgement {
+	hashes, err := slacker.ackHashes()
+	if err != nil {
+		slacker.Log.Errorf("Slacker failed to get acknowledgement: %s", err)
+		return acknowledgement{}
+	}
+
+	db, err := slacker.getRecords(hashes)
+	if err != nil {
+		slacker.Log.Errorf("Slacker failed to get acknowledgement: %s", err)
+		return acknowledgement{}
+	}
+
+	for _, hash := range hashes {
+		if record, ok := db[hash]; ok && record.AckedBy != "" {
+			return acknowledgement{by: record.AckedBy, at: record.Time}
+		}
+	}
+
+	return acknowledgement{}
+}
+
+// clearAcknowledgement forgets acknowledgements of the resolved alert of the tag
+func (slacker Slacker) clearAcknowledgement(now time.Time) {
+	hashes, err := slacker.ackHashes()
+	if err != nil {
+		slacker.Log.Errorf("Slacker failed to clear acknowledgement: %s", err)
+		return
+	}
+
+	db, err := slacker.getRecords(hashes)
+	if err != nil {
+		slacker.Log.Errorf("Slacker failed to clear acknowledgement: %s", err)
+		return
+	}
+
+	for _, hash := range hashes {
+		if db[hash].AckedBy == "" {
+			continue
+		}
+
+		err := slacker.updateCounter(hash, func(record *dbRecord) *dbRecord {
+			return &dbRecord{State: recordConfirmed, Time: now}
+		})
+		if err != nil {
+			slacker.Log.Errorf("Slacker failed to clear acknowledgement: %s", err)
+		}
+	}
+}
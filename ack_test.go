@@ -0,0 +1,101 @@
+package slacker
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAcknowledge(t *testing.T) {
+	hook := newTestHook(t)
+	slacker := newTestSlacker(t, hook)
+	slacker.Frequency = NotifyAlways
+	slacker.Acknowledgements = true
+
+	if err := slacker.Acknowledge(slacker.MessageTag, "alice"); err != nil {
+		t.Fatalf("First Acknowledge failed: %s", err)
+	}
+	if err := slacker.Acknowledge(slacker.MessageTag, "bob"); err != nil {
+		t.Fatalf("Second Acknowledge failed: %s", err)
+	}
+
+	if err := slacker.Send("disk full"); err != nil {
+		t.Fatal(err)
+	}
+	if posted := hook.posted("acked by bob at "); len(posted) != 1 {
+		t.Fatalf("Message is not annotated with the acknowledgement: %v", hook.payloads)
+	}
+
+	if err := slacker.Resolve("disk ok"); err != nil {
+		t.Fatal(err)
+	}
+	if err := slacker.Send("disk full again"); err != nil {
+		t.Fatal(err)
+	}
+	if posted := hook.posted("disk full again"); len(posted) != 1 || strings.Contains(posted[0], "acked by") {
+		t.Fatalf("Acknowledgement is not cleared by Resolve: %v", posted)
+	}
+}
+
+func TestAcknowledgeByMessageId(t *testing.T) {
+	hook := newTestHook(t)
+	slacker := newTestSlacker(t, hook)
+	slacker.Acknowledgements = true
+
+	id, err := slacker.SendWithId("disk full")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := slacker.Acknowledge(id, "alice"); err != nil {
+		t.Fatal(err)
+	}
+
+	if ack := slacker.acknowledgement(); ack.by != "alice" {
+		t.Fatalf("Message id acknowledgement is not found: %+v", ack)
+	}
+}
+
+func TestAcknowledgeHaltsEscalations(t *testing.T) {
+	transport := &smsTransport{}
+	slacker := newSmsSlacker(t, transport, []string{"test"})
+	if err := slacker.Acknowledge("test", "alice"); err != nil {
+		t.Fatal(err)
+	}
+	slacker.Send("down")
+	if len(transport.bodies) != 0 {
+		t.Fatal("SMS fallback is sent for an acknowledged alert")
+	}
+
+	hook := newTestHook(t)
+	slacker = newTestSlacker(t, hook)
+	slacker.CrashLoopThreshold = 1
+	if err := slacker.Acknowledge("crashloop:api:1.0", "alice"); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 3; i++ {
+		if err := slacker.AnnounceStartup("api", "1.0", nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if len(hook.posted("Possible crash loop")) != 0 {
+		t.Fatalf("Crash loop alert is sent while acknowledged: %v", hook.payloads)
+	}
+
+	slacker.Statistics = true
+	last := time.Now().UTC().Truncate(time.Hour).Add(-time.Hour)
+	for hour := 7; hour > 0; hour-- {
+		for i := 0; i < 5; i++ {
+			slacker.recordStats(true, last.Add(-time.Duration(hour)*time.Hour))
+		}
+	}
+	if err := slacker.Acknowledge("test", "alice"); err != nil {
+		t.Fatal(err)
+	}
+	anomalies, err := slacker.CheckVolume(AnomalyConfig{}, "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(anomalies) != 1 || len(hook.posted("No notifications of test")) != 0 {
+		t.Fatalf("Anomaly alert of %v is sent while acknowledged: %v", anomalies, hook.payloads)
+	}
+}
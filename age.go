@@ -0,0 +1,116 @@
+package slacker
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// seenHash is the database key of the firing alert of the tag,
+// its record Time is when the alert was first seen
+func (slacker Slacker) seenHash() string {
+	return "seen:" + slacker.MessageTag
+}
+
+// alertAge holds when the alert of a tag was first seen and whether it was seen before
+type alertAge struct {
+	firstSeen time.Time
+	repeated  bool
+}
+
+// annotate appends how long the alert is firing to message of a repeated alert
+func (age alertAge) annotate(message string, now time.Time) string {
+	if !age.repeated {
+		return message
+	}
+
+	return fmt.Sprintf("%s (firing for %s)", message, formatAge(now.Sub(age.firstSeen)))
+}
+
+// recordSeen updates last seen time of the alert of the tag to now,
+// the first message of an alert starts it
+func (slacker Slacker) recordSeen(now time.Time) alertAge {
+	var age alertAge
+	err := slacker.updateCounter(slacker.seenHash(), func(record *dbRecord) *dbRecord {
+		next := &dbRecord{State: recordConfirmed, Time: now, LastSeen: &now}
+		age = alertAge{firstSeen: now}
+		if record != nil && record.LastSeen != nil {
+			next.Time = record.Time
+			age = alertAge{firstSeen: record.Time, repeated: true}
+		}
+		return next
+	})
+	if err != nil {
+		slacker.Log.Errorf("Slacker failed to update alert age: %s", err)
+	}
+
+	return age
+}
+
+// Resolve sends message telling the alert of MessageTag is over and how long it was firing.
+// It is never suppressed, and the next message of the tag starts a new alert and a new thread
+// and is not acknowledged.
+func (slacker Slacker) Resolve(message string) error {
+	if err := slacker.setDefaults(); err != nil {
+		return fmt.Errorf("Slacker failed to send message: %s", err)
+	}
+
+	slacker.ctx = context.Background()
+	slacker.messageId = slacker.newMessageId()
+	slacker.resolving = true
+
+	now := time.Now()
+	hash := slacker.seenHash()
+	for attempt := 0; attempt < maxUpdateAttempts; attempt++ {
+		record, found, err := slacker.getRecord(hash)
+		if err != nil {
+			return fmt.Errorf("Slacker failed to resolve alert: %s", err)
+		}
+		if !found || record.LastSeen == nil {
+			break
+		}
+
+		err = slacker.putRecord(hash, &dbRecord{State: recordConfirmed, Time: now, Version: record.Version})
+		if err == errConflict {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("Slacker failed to resolve alert: %s", err)
+		}
+
+		message = fmt.Sprintf("%s (was firing for %s)", message, formatAge(now.Sub(record.Time)))
+		break
+	}
+
+	if err := slacker.deliver(message, "", nil); err != nil {
+		return err
+	}
+
+	slacker.closeThreads()
+	slacker.clearAcknowledgement(now)
+	return nil
+}
+
+// formatAge formats d with its two most significant units, e.g. "3h 12m", "2d 5h" or "45m"
+func formatAge(d time.Duration) string {
+	if d < time.Minute {
+		return "less than a minute"
+	}
+
+	days := int(d / (24 * time.Hour))
+	hours := int(d % (24 * time.Hour) / time.Hour)
+	minutes := int(d % time.Hour / time.Minute)
+
+	switch {
+	case days > 0 && hours > 0:
+		return fmt.Sprintf("%dd %dh", days, hours)
+	case days > 0:
+		return fmt.Sprintf("%dd", days)
+	case hours > 0 && minutes > 0:
+		return fmt.Sprintf("%dh %dm", hours, minutes)
+	case hours > 0:
+		return fmt.Sprintf("%dh", hours)
+	}
+
+	return fmt.Sprintf("%dm", minutes)
+}
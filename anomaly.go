@@ -0,0 +1,142 @@
+package slacker
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+const (
+	DefaultAnomalyThreshold   float64 = 4
+	DefaultAnomalyAlpha       float64 = 0.3
+	DefaultAnomalyMinBaseline float64 = 5
+
+	// anomalyMinHistory is the number of hour buckets needed before the evaluated one
+	anomalyMinHistory = 6
+)
+
+// AnomalyConfig tunes CheckVolume, zero fields take defaults
+type AnomalyConfig struct {
+	Threshold   float64 // Standard deviations above the baseline which make a storm, DefaultAnomalyThreshold by default
+	Alpha       float64 // EWMA smoothing factor from 0 to 1, DefaultAnomalyAlpha by default
+	MinBaseline float64 // Baseline per hour below which silence is normal, DefaultAnomalyMinBaseline by default
+}
+
+// VolumeAnomaly is an hour with notification volume of a tag far from its baseline
+type VolumeAnomaly struct {
+	MessageTag string
+	Start      time.Time // Start of the hour
+	Count      int       // Messages sent and suppressed within the hour
+	Baseline   float64   // Expected messages per hour
+	Silence    bool      // True if messages stopped, false if they stormed
+}
+
+func (anomaly VolumeAnomaly) String() string {
+	if anomaly.Silence {
+		return fmt.Sprintf("No notifications of %s within the hour since %s, usually %.1f per hour",
+			anomaly.MessageTag, anomaly.Start.Format("15:04"), anomaly.Baseline)
+	}
+
+	return fmt.Sprintf("%d notifications of %s within the hour since %s, usually %.1f per hour",
+		anomaly.Count, anomaly.MessageTag, anomaly.Start.Format("15:04"), anomaly.Baseline)
+}
+
+// CheckVolume compares the last complete hour of messages of every tag, counted with Statistics,
+// to the EWMA baseline of hours before it. Anomalies are returned and sent as meta-alerts
+// tagged "anomaly:<tag>" at most once per hour, unless the tag or the meta-alert is acknowledged.
+// Call it periodically, e.g. every few minutes.
+func (slacker Slacker) CheckVolume(config AnomalyConfig, tags ...string) (anomalies []VolumeAnomaly, err error) {
+	if err := slacker.setDefaults(); err != nil {
+		return nil, fmt.Errorf("Slacker failed to check volume: %s", err)
+	}
+
+	if config.Threshold <= 0 {
+		config.Threshold = DefaultAnomalyThreshold
+	}
+	if config.Alpha <= 0 || config.Alpha > 1 {
+		config.Alpha = DefaultAnomalyAlpha
+	}
+	if config.MinBaseline <= 0 {
+		config.MinBaseline = DefaultAnomalyMinBaseline
+	}
+
+	last := time.Now().UTC().Truncate(time.Hour).Add(-time.Hour)
+	for _, tag := range tags {
+		tagged := slacker
+		tagged.MessageTag = tag
+
+		buckets, err := tagged.Stats(time.Hour, time.Time{})
+		if err != nil {
+			return anomalies, err
+		}
+
+		anomaly, ok := detectAnomaly(buckets, last, config)
+		if !ok {
+			continue
+		}
+		anomaly.MessageTag = tag
+		anomalies = append(anomalies, anomaly)
+
+		alert := slacker
+		alert.MessageTag = "anomaly:" + tag
+		alert.Frequency = NotifyOnceHour
+		alert.FrequencyDuration = 0
+		alert.Statistics = false
+
+		ack := alert.acknowledgement()
+		if ack.by == "" {
+			ack = tagged.acknowledgement()
+		}
+		if ack.by != "" {
+			slacker.Log.Infof("Skip anomaly alert %s acknowledged by %s", alert.MessageTag, ack.by)
+			continue
+		}
+
+		if err := alert.Send(anomaly.String()); err != nil {
+			return anomalies, err
+		}
+	}
+
+	return anomalies, nil
+}
+
+// detectAnomaly evaluates the hour bucket starting at last against the EWMA of the hours before it
+func detectAnomaly(buckets []StatsBucket, last time.Time, config AnomalyConfig) (VolumeAnomaly, bool) {
+	if len(buckets) == 0 {
+		return VolumeAnomaly{}, false
+	}
+
+	counts := make(map[time.Time]int, len(buckets))
+	for _, bucket := range buckets {
+		counts[bucket.Start.UTC()] = bucket.Sent + bucket.Suppressed
+	}
+
+	first := buckets[0].Start.UTC()
+	if last.Sub(first) < anomalyMinHistory*time.Hour {
+		return VolumeAnomaly{}, false
+	}
+
+	mean := float64(counts[first])
+	variance := 0.0
+	for hour := first.Add(time.Hour); hour.Before(last); hour = hour.Add(time.Hour) {
+		diff := float64(counts[hour]) - mean
+		increment := config.Alpha * diff
+		mean += increment
+		variance = (1 - config.Alpha) * (variance + diff*increment)
+	}
+
+	count := counts[last]
+	deviation := math.Max(math.Sqrt(variance), 1)
+	anomaly := VolumeAnomaly{Start: last, Count: count, Baseline: mean}
+
+	if float64(count) > mean+config.Threshold*deviation {
+		return anomaly, true
+	}
+
+	if count == 0 && mean >= config.MinBaseline {
+		anomaly.Silence = true
+		return anomaly, true
+	}
+
+	return VolumeAnomaly{}, false
+}
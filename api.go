@@ -0,0 +1,47 @@
+package slacker
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// ApiError is a Web API response with "ok": false, e.g. "channel_not_found" or "invalid_auth"
+type ApiError struct {
+	Code string
+	Body string
+}
+
+func (err *ApiError) Error() string {
+	return fmt.Sprintf("Response from Slack Web API: %s, body: %s", err.Code, truncate(err.Body, maxDiagnosticsLength))
+}
+
+type apiResponse struct {
+	Ok    bool   `json:"ok"`
+	Error string `json:"error"`
+	Ts    string `json:"ts"`
+}
+
+// postUrl returns the url messages are posted to, chat.postMessage if BotToken is set or Hook
+func (slacker Slacker) postUrl() string {
+	if slacker.BotToken == "" {
+		return slacker.Hook
+	}
+
+	return strings.TrimSuffix(slacker.ApiUrl, "/") + "/chat.postMessage"
+}
+
+// checkApiResponse returns ApiError if Web API response body is not ok.
+// The Web API answers errors with 200 OK, unlike incoming webhooks.
+func checkApiResponse(body string) error {
+	var response apiResponse
+	if err := json.Unmarshal([]byte(body), &response); err != nil {
+		return fmt.Errorf("Failed to decode response from Slack Web API: %s, body: %s", err, truncate(body, maxDiagnosticsLength))
+	}
+
+	if !response.Ok {
+		return &ApiError{Code: response.Error, Body: body}
+	}
+
+	return nil
+}
@@ -0,0 +1,42 @@
+package slacker
+
+import "strings"
+
+// Attachment colors understood by Slack, any hex color like "#439FE0" works too
+const (
+	ColorGood    string = "good"
+	ColorWarning string = "warning"
+	ColorDanger  string = "danger"
+)
+
+// Attachment is a classic Slack message attachment with a color bar
+type Attachment struct {
+	Fallback   string            `json:"fallback,omitempty"`
+	Color      string            `json:"color,omitempty"`
+	Pretext    string            `json:"pretext,omitempty"`
+	Title      string            `json:"title,omitempty"`
+	TitleLink  string            `json:"title_link,omitempty"`
+	Text       string            `json:"text,omitempty"`
+	Fields     []AttachmentField `json:"fields,omitempty"`
+	Footer     string            `json:"footer,omitempty"`
+	FooterIcon string            `json:"footer_icon,omitempty"`
+	Timestamp  int64             `json:"ts,omitempty"`
+}
+
+// AttachmentField is a title and value shown in a table, two in a row if Short
+type AttachmentField struct {
+	Title string `json:"title"`
+	Value string `json:"value"`
+	Short bool   `json:"short"`
+}
+
+// SendAttachment sends att like Send. Fallback, or title and text if it is empty,
+// is the plain text of the attachment and what gets deduplicated.
+func (slacker Slacker) SendAttachment(att Attachment) error {
+	if att.Fallback == "" {
+		att.Fallback = strings.TrimSpace(att.Title + "\n" + att.Text)
+	}
+
+	slacker.attachments = []Attachment{att}
+	return slacker.Send(att.Fallback)
+}
@@ -0,0 +1,203 @@
+package slacker
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Message is a text tagged by MessageTag
+type Message struct {
+	MessageTag string
+	Text       string
+}
+
+// SendResult holds the outcome of a Message sent by SendBatch
+type SendResult struct {
+	Message   Message
+	MessageId string // Of the message which suppressed it if it was suppressed
+	Sent      bool   // False if the message was suppressed by Frequency or collected for a digest
+	Err       error
+}
+
+// SendBatch sends messages with Slacker settings and each message own MessageTag.
+// Messages go through Enrichers, AlertAge and DigestWindow like Send and are deduplicated one by one,
+// messages let through are combined into a single post per recipient and records of all messages
+// are written with two appends to the database.
+func (slacker Slacker) SendBatch(messages []Message) (results []SendResult, err error) {
+	if err := slacker.setDefaults(); err != nil {
+		return nil, fmt.Errorf("Slacker failed to send batch: %s", err)
+	}
+
+	results = make([]SendResult, len(messages))
+	for i, message := range messages {
+		results[i].Message = message
+		results[i].MessageId = slacker.newMessageId()
+	}
+
+	if slacker.inWarmUp() {
+		slacker.Log.Debugf("Skip batch of %d messages during warm-up", len(messages))
+		for _, message := range messages {
+			slacker.emit(EventSuppress, "", message.Text, nil)
+		}
+		return results, nil
+	}
+
+	now := time.Now()
+	texts := make([]string, len(messages))
+	for i, message := range messages {
+		texts[i] = slacker.batchTagged(message, results[i].MessageId).prepare(message.Text, now)
+	}
+
+	if slacker.DigestWindow > 0 {
+		for i, message := range messages {
+			id, err := slacker.batchTagged(message, results[i].MessageId).sendDigest(texts[i], now)
+			results[i].MessageId = id
+			results[i].Sent = id != "" && err == nil
+			results[i].Err = err
+		}
+		return results, nil
+	}
+
+	var lookup []string
+	if !slacker.isAlways() {
+		for _, message := range messages {
+			lookup = append(lookup, slacker.batchTagged(message, "").getHashAt(now))
+		}
+	}
+
+	db, err := slacker.getRecords(lookup)
+	if err != nil {
+		slacker.Log.Errorf("Slacker failed to send batch: %s", err)
+		slacker.emit(EventError, "", "", err)
+		return results, err
+	}
+
+	var accepted []int
+	hashes := make(map[int]string)
+	pending := make(map[string]*dbRecord)
+	for i, message := range messages {
+		tagged := slacker.batchTagged(message, results[i].MessageId)
+
+		if !slacker.isAlways() {
+			hash := tagged.getHashAt(now)
+			record, found := db[hash]
+			if pending[hash] != nil {
+				slacker.Log.Debugf("Skip duplicate message %s in batch: %s", hash, texts[i])
+				tagged.emit(EventSuppress, hash, texts[i], nil)
+				results[i].MessageId = pending[hash].MessageId
+				continue
+			}
+
+			if !slacker.isDue(record, found, now) {
+				slacker.Log.Debugf("Skip message %s: %s", hash, texts[i])
+				tagged.emit(EventSuppress, hash, texts[i], nil)
+				if slacker.SuppressionNotice {
+					tagged.recordSuppressed(texts[i], now)
+				}
+				results[i].MessageId = record.MessageId
+				continue
+			}
+
+			if found && record.State == recordPending && isInFlight(record, now) {
+				slacker.Log.Debugf("Skip message %s being sent by another process: %s", hash, texts[i])
+				tagged.emit(EventSuppress, hash, texts[i], nil)
+				results[i].MessageId = record.MessageId
+				continue
+			}
+
+			if found && record.State == recordPending {
+				slacker.Log.Infof("Resend pending message %s: %s", hash, texts[i])
+			}
+
+			pending[hash] = &dbRecord{
+				Message:   texts[i],
+				MessageId: results[i].MessageId,
+				State:     recordPending,
+				Time:      now,
+				Version:   record.Version,
+			}
+			hashes[i] = hash
+		}
+
+		accepted = append(accepted, i)
+	}
+
+	if len(pending) > 0 {
+		conflicts, err := slacker.putRecords(pending)
+		if err != nil {
+			slacker.Log.Errorf("Slacker failed to send batch: %s", err)
+			slacker.emit(EventError, "", "", err)
+			return results, err
+		}
+
+		var own []int
+		for _, i := range accepted {
+			if hash, ok := hashes[i]; ok && conflicts[hash] {
+				slacker.Log.Debugf("Skip message %s sent by another process: %s", hash, texts[i])
+				delete(pending, hash)
+				results[i].MessageId = ""
+				continue
+			}
+			own = append(own, i)
+		}
+		accepted = own
+	}
+
+	if len(accepted) == 0 {
+		return results, nil
+	}
+
+	var lines []string
+	for _, i := range accepted {
+		text := texts[i]
+		if slacker.SuppressionNotice {
+			text = slacker.batchTagged(messages[i], results[i].MessageId).takeSuppressionNotice() + text
+		}
+		lines = append(lines, text)
+	}
+
+	err = slacker.deliver(strings.Join(lines, "\n"), "", nil)
+	for _, i := range accepted {
+		results[i].Sent = err == nil
+		results[i].Err = err
+	}
+	if err != nil {
+		// Failed records are not in flight, so the messages are sent again without waiting for the lease
+		if len(pending) > 0 {
+			for _, record := range pending {
+				record.Failed = true
+			}
+			if _, putErr := slacker.putRecords(pending); putErr != nil {
+				slacker.Log.Errorf("Slacker failed to release batch in database: %s", putErr)
+			}
+		}
+		return results, err
+	}
+
+	if len(pending) > 0 {
+		for _, record := range pending {
+			record.State = recordConfirmed
+			record.Time = time.Now()
+			record.DryRun = slacker.DryRun
+		}
+
+		if _, err := slacker.putRecords(pending); err != nil {
+			slacker.Log.Errorf("Slacker failed to send batch: %s", err)
+			slacker.emit(EventError, "", "", err)
+			return results, err
+		}
+	}
+
+	return results, nil
+}
+
+// batchTagged returns slacker sending message of a batch with id
+func (slacker Slacker) batchTagged(message Message, id string) Slacker {
+	if message.MessageTag != "" {
+		slacker.MessageTag = message.MessageTag
+	}
+	slacker.messageId = id
+
+	return slacker
+}
@@ -0,0 +1,83 @@
+package slacker
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSendBatchReleasesFailedMessages(t *testing.T) {
+	hook := newTestHook(t)
+	slacker := newTestSlacker(t, hook)
+
+	hook.setFail(true)
+	if _, err := slacker.SendBatch([]Message{{Text: "a"}}); err == nil {
+		t.Fatal("SendBatch succeeded with failing hook")
+	}
+
+	hook.setFail(false)
+	if err := slacker.Send("b"); err != nil {
+		t.Fatal(err)
+	}
+	if len(hook.posted(`" b"`)) != 1 {
+		t.Fatalf("Message of the failed batch tag is not sent: %v", hook.payloads)
+	}
+}
+
+// suffixEnricher appends its suffix to messages
+type suffixEnricher string
+
+func (suffix suffixEnricher) Enrich(message Message) (Message, error) {
+	message.Text += string(suffix)
+	return message, nil
+}
+
+func TestSendBatchSuppressedMessageIds(t *testing.T) {
+	hook := newTestHook(t)
+	slacker := newTestSlacker(t, hook)
+
+	id, err := slacker.SendWithId("first")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	results, err := slacker.SendBatch([]Message{{Text: "again"}, {MessageTag: "other", Text: "new"}, {MessageTag: "other", Text: "duplicate"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if results[0].Sent || results[0].MessageId != id {
+		t.Fatalf("Suppressed message has id %s, not %s of the message which suppressed it", results[0].MessageId, id)
+	}
+	if !results[1].Sent || results[2].Sent || results[2].MessageId != results[1].MessageId {
+		t.Fatalf("Duplicate in batch has id %s, not %s", results[2].MessageId, results[1].MessageId)
+	}
+}
+
+func TestSendBatchPipeline(t *testing.T) {
+	hook := newTestHook(t)
+	slacker := newTestSlacker(t, hook)
+	slacker.Enrichers = []Enricher{suffixEnricher(" enriched")}
+
+	if _, err := slacker.SendBatch([]Message{{Text: "batched"}}); err != nil {
+		t.Fatal(err)
+	}
+	if len(hook.posted("batched enriched")) != 1 {
+		t.Fatalf("Batch message is not enriched: %v", hook.payloads)
+	}
+
+	slacker.DigestWindow = time.Hour
+	results, err := slacker.SendBatch([]Message{{MessageTag: "digest", Text: "collected"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if results[0].Sent || len(hook.posted("collected")) != 0 {
+		t.Fatalf("Batch message is not collected for the digest: %v", hook.payloads)
+	}
+
+	slacker.MessageTag = "digest"
+	if err := slacker.FlushDigest(); err != nil {
+		t.Fatal(err)
+	}
+	if len(hook.posted("collected enriched")) != 1 {
+		t.Fatalf("Digest of the batch message is not sent: %v", hook.payloads)
+	}
+}
@@ -0,0 +1,114 @@
+package slacker
+
+import "strings"
+
+// Block types
+const (
+	BlockSection string = "section"
+	BlockDivider string = "divider"
+	BlockHeader  string = "header"
+	BlockActions string = "actions"
+)
+
+// Text object types
+const (
+	TextPlain    string = "plain_text"
+	TextMarkdown string = "mrkdwn"
+)
+
+// Block is a Slack Block Kit layout block
+type Block struct {
+	Type      string         `json:"type"`
+	BlockId   string         `json:"block_id,omitempty"`
+	Text      *TextObject    `json:"text,omitempty"`
+	Fields    []TextObject   `json:"fields,omitempty"`
+	Accessory *BlockElement  `json:"accessory,omitempty"`
+	Elements  []BlockElement `json:"elements,omitempty"`
+}
+
+// TextObject is a Block Kit text, TextPlain or TextMarkdown
+type TextObject struct {
+	Type  string `json:"type"`
+	Text  string `json:"text"`
+	Emoji bool   `json:"emoji,omitempty"`
+}
+
+// BlockElement is a Block Kit button element
+type BlockElement struct {
+	Type     string      `json:"type"`
+	Text     *TextObject `json:"text,omitempty"`
+	ActionId string      `json:"action_id,omitempty"`
+	Url      string      `json:"url,omitempty"`
+	Value    string      `json:"value,omitempty"`
+	Style    string      `json:"style,omitempty"` // "primary" or "danger"
+}
+
+// PlainText returns a plain text object
+func PlainText(text string) TextObject {
+	return TextObject{Type: TextPlain, Text: text, Emoji: true}
+}
+
+// MarkdownText returns a mrkdwn text object
+func MarkdownText(text string) TextObject {
+	return TextObject{Type: TextMarkdown, Text: text}
+}
+
+// NewSectionBlock returns a section with mrkdwn text and optional mrkdwn fields shown in two columns
+func NewSectionBlock(text string, fields ...string) Block {
+	block := Block{Type: BlockSection}
+	if text != "" {
+		markdown := MarkdownText(text)
+		block.Text = &markdown
+	}
+
+	for _, field := range fields {
+		block.Fields = append(block.Fields, MarkdownText(field))
+	}
+
+	return block
+}
+
+// NewHeaderBlock returns a header with plain text
+func NewHeaderBlock(text string) Block {
+	plain := PlainText(text)
+	return Block{Type: BlockHeader, Text: &plain}
+}
+
+// NewDividerBlock returns a divider
+func NewDividerBlock() Block {
+	return Block{Type: BlockDivider}
+}
+
+// NewActionsBlock returns a block of buttons
+func NewActionsBlock(buttons ...BlockElement) Block {
+	return Block{Type: BlockActions, Elements: buttons}
+}
+
+// NewButton returns a button opening url
+func NewButton(text string, url string) BlockElement {
+	plain := PlainText(text)
+	return BlockElement{Type: "button", Text: &plain, Url: url}
+}
+
+// SendBlocks sends Block Kit blocks like Send. The notification text of the message
+// is made of block texts and fields, and it is also what is deduplicated.
+func (slacker Slacker) SendBlocks(blocks []Block) error {
+	slacker.blocks = blocks
+	return slacker.Send(blocksText(blocks))
+}
+
+// blocksText returns texts and fields of blocks line by line
+func blocksText(blocks []Block) string {
+	var lines []string
+	for _, block := range blocks {
+		if block.Text != nil {
+			lines = append(lines, block.Text.Text)
+		}
+
+		for _, field := range block.Fields {
+			lines = append(lines, field.Text)
+		}
+	}
+
+	return strings.Join(lines, "\n")
+}
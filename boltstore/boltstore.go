@@ -0,0 +1,188 @@
+// Package boltstore keeps Slacker records in a bbolt database file,
+// so every Send writes only its own records in a transaction instead of appending to a JSON file.
+//
+// Slacker is built in GOPATH mode without go.mod, so bbolt has to be checked out
+// at the version boltstore is tested with:
+//
+//	git clone --branch v1.3.10 https://github.com/etcd-io/bbolt $GOPATH/src/go.etcd.io/bbolt
+//	go build github.com/oneumyvakin/slacker/boltstore
+package boltstore
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/oneumyvakin/slacker"
+	bolt "go.etcd.io/bbolt"
+)
+
+const sweepInterval = time.Minute
+
+var bucketName = []byte("records")
+
+// Store implements slacker.Store on a bbolt database file
+type Store struct {
+	db *bolt.DB
+
+	mutex sync.Mutex
+	swept time.Time
+}
+
+type storedRecord struct {
+	Value   []byte    `json:"value"`
+	Version int       `json:"version"`
+	Expires time.Time `json:"expires"`
+}
+
+// Open opens or creates the bbolt database file path.
+// The file is locked by the process until Close, other processes wait for up to timeout to open it.
+func Open(path string, timeout time.Duration) (*Store, error) {
+	db, err := bolt.Open(path, 0644, &bolt.Options{Timeout: timeout})
+	if err != nil {
+		return nil, fmt.Errorf("Failed to open bolt database %s: %s", path, err)
+	}
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(bucketName)
+		return err
+	})
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("Failed to create bucket in bolt database %s: %s", path, err)
+	}
+
+	return &Store{db: db}, nil
+}
+
+// Close closes the database file
+func (store *Store) Close() error {
+	return store.db.Close()
+}
+
+// Get implements slacker.Store
+func (store *Store) Get(key string) (record slacker.StoreRecord, found bool, err error) {
+	err = store.db.View(func(tx *bolt.Tx) error {
+		stored, ok, err := getRecord(tx.Bucket(bucketName), key, time.Now())
+		if err != nil || !ok {
+			return err
+		}
+
+		record = slacker.StoreRecord{Key: key, Value: stored.Value, Version: stored.Version, Expires: stored.Expires}
+		found = true
+		return nil
+	})
+
+	return record, found, err
+}
+
+// Put implements slacker.Store, all records are written in one transaction
+func (store *Store) Put(records []slacker.StoreRecord) (conflicts map[string]bool, err error) {
+	now := time.Now()
+	conflicts = make(map[string]bool)
+
+	err = store.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(bucketName)
+		if store.sweepDue(now) {
+			if _, err := sweep(bucket, now); err != nil {
+				return err
+			}
+		}
+
+		for _, record := range records {
+			stored, _, err := getRecord(bucket, record.Key, now)
+			if err != nil {
+				return err
+			}
+
+			if !record.Force && stored.Version != record.Version {
+				conflicts[record.Key] = true
+				continue
+			}
+
+			value, err := json.Marshal(storedRecord{Value: record.Value, Version: stored.Version + 1, Expires: record.Expires})
+			if err != nil {
+				return err
+			}
+
+			if err := bucket.Put([]byte(record.Key), value); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return conflicts, nil
+}
+
+// Prune removes expired records and returns their number
+func (store *Store) Prune() (pruned int, err error) {
+	err = store.db.Update(func(tx *bolt.Tx) error {
+		pruned, err = sweep(tx.Bucket(bucketName), time.Now())
+		return err
+	})
+
+	return pruned, err
+}
+
+func (store *Store) sweepDue(now time.Time) bool {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	if now.Sub(store.swept) < sweepInterval {
+		return false
+	}
+
+	store.swept = now
+	return true
+}
+
+// getRecord returns the record of key in bucket, found is false if there is none or it expired at now
+func getRecord(bucket *bolt.Bucket, key string, now time.Time) (stored storedRecord, found bool, err error) {
+	value := bucket.Get([]byte(key))
+	if value == nil {
+		return stored, false, nil
+	}
+
+	if err := json.Unmarshal(value, &stored); err != nil {
+		return stored, false, fmt.Errorf("Failed to decode record %s: %s", key, err)
+	}
+
+	if !stored.Expires.IsZero() && !now.Before(stored.Expires) {
+		return storedRecord{}, false, nil
+	}
+
+	return stored, true, nil
+}
+
+// sweep deletes records of bucket expired at now
+func sweep(bucket *bolt.Bucket, now time.Time) (pruned int, err error) {
+	var expired [][]byte
+	err = bucket.ForEach(func(key []byte, value []byte) error {
+		var stored storedRecord
+		if err := json.Unmarshal(value, &stored); err != nil {
+			return nil
+		}
+
+		if !stored.Expires.IsZero() && !now.Before(stored.Expires) {
+			expired = append(expired, append([]byte(nil), key...))
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	for _, key := range expired {
+		if err := bucket.Delete(key); err != nil {
+			return 0, err
+		}
+	}
+
+	return len(expired), nil
+}
@@ -7,6 +7,9 @@ set GOOS=linux
 set GOARCH=amd64
 go build %PKGNAME%
 
+REM Stores need their dependencies checked out, see the package docs of boltstore and redisstore
+go build %PKGNAME%/sqlitestore %PKGNAME%/boltstore %PKGNAME%/redisstore
+
 set GOOS=windows
 set GOARCH=amd64
 go build %PKGNAME%
\ No newline at end of file
@@ -0,0 +1,116 @@
+package slacker
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	DefaultChainFailureThreshold int           = 3
+	DefaultChainCooldown         time.Duration = time.Minute
+)
+
+// Chain is a Notifier trying its notifiers in order until one delivers the message,
+// e.g. Slack, then Google Chat, then email.
+// A notifier which failed FailureThreshold times in a row is skipped for Cooldown,
+// after which it gets one more try.
+type Chain struct {
+	Notifiers        []Notifier
+	FailureThreshold int           // Optional, DefaultChainFailureThreshold by default
+	Cooldown         time.Duration // Optional, DefaultChainCooldown by default
+
+	mutex     sync.Mutex
+	failures  map[int]int
+	openUntil map[int]time.Time
+}
+
+// NewChain returns Chain of notifiers with default health settings
+func NewChain(notifiers ...Notifier) *Chain {
+	return &Chain{Notifiers: notifiers}
+}
+
+// Notify implements Notifier
+func (chain *Chain) Notify(message Message) error {
+	_, err := chain.DeliverContext(context.Background(), message)
+	return err
+}
+
+// NotifyContext implements ContextNotifier, ctx is passed to the notifiers which are ContextNotifier
+func (chain *Chain) NotifyContext(ctx context.Context, message Message) error {
+	_, err := chain.DeliverContext(ctx, message)
+	return err
+}
+
+// Deliver sends message like Notify and returns the index of the notifier which delivered it
+func (chain *Chain) Deliver(message Message) (delivered int, err error) {
+	return chain.DeliverContext(context.Background(), message)
+}
+
+// DeliverContext sends message like NotifyContext and returns the index of the notifier which delivered it.
+// The next notifiers are not tried once ctx is done.
+func (chain *Chain) DeliverContext(ctx context.Context, message Message) (delivered int, err error) {
+	var errs []string
+	for i, notifier := range chain.Notifiers {
+		if err := ctx.Err(); err != nil {
+			errs = append(errs, err.Error())
+			break
+		}
+
+		if chain.isOpen(i) {
+			errs = append(errs, fmt.Sprintf("%T skipped after repeated failures", notifier))
+			continue
+		}
+
+		err := notify(ctx, notifier, message)
+		chain.record(i, err)
+		if err == nil {
+			return i, nil
+		}
+
+		errs = append(errs, fmt.Sprintf("%T: %s", notifier, err))
+	}
+
+	return -1, fmt.Errorf("No notifier in chain delivered the message: %s", strings.Join(errs, "; "))
+}
+
+// isOpen reports whether notifier i is skipped after repeated failures
+func (chain *Chain) isOpen(i int) bool {
+	chain.mutex.Lock()
+	defer chain.mutex.Unlock()
+
+	return time.Now().Before(chain.openUntil[i])
+}
+
+func (chain *Chain) record(i int, err error) {
+	chain.mutex.Lock()
+	defer chain.mutex.Unlock()
+
+	if chain.failures == nil {
+		chain.failures = make(map[int]int)
+		chain.openUntil = make(map[int]time.Time)
+	}
+
+	if err == nil {
+		delete(chain.failures, i)
+		delete(chain.openUntil, i)
+		return
+	}
+
+	threshold := chain.FailureThreshold
+	if threshold <= 0 {
+		threshold = DefaultChainFailureThreshold
+	}
+
+	cooldown := chain.Cooldown
+	if cooldown <= 0 {
+		cooldown = DefaultChainCooldown
+	}
+
+	chain.failures[i]++
+	if chain.failures[i] >= threshold {
+		chain.openUntil[i] = time.Now().Add(cooldown)
+	}
+}
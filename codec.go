@@ -0,0 +1,97 @@
+package slacker
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// Codec encodes data Slacker persists, e.g. to encrypt or sign database and event files at rest.
+// Decode must reject data not produced by Encode.
+type Codec interface {
+	Encode(plain []byte) ([]byte, error)
+	Decode(encoded []byte) ([]byte, error)
+}
+
+// AesCodec encrypts and authenticates data with AES-GCM
+type AesCodec struct {
+	aead cipher.AEAD
+}
+
+// NewAesCodec returns AesCodec with a 16, 24 or 32 bytes key for AES-128, AES-192 or AES-256
+func NewAesCodec(key []byte) (*AesCodec, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to create AES cipher: %s", err)
+	}
+
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to create AES-GCM cipher: %s", err)
+	}
+
+	return &AesCodec{aead: aead}, nil
+}
+
+// Encode implements Codec, the random nonce is prepended to the sealed data
+func (codec *AesCodec) Encode(plain []byte) ([]byte, error) {
+	nonce := make([]byte, codec.aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, fmt.Errorf("Failed to generate nonce: %s", err)
+	}
+
+	return codec.aead.Seal(nonce, nonce, plain, nil), nil
+}
+
+// Decode implements Codec
+func (codec *AesCodec) Decode(encoded []byte) ([]byte, error) {
+	if len(encoded) < codec.aead.NonceSize() {
+		return nil, errors.New("Encoded data is shorter than nonce")
+	}
+
+	nonce, sealed := encoded[:codec.aead.NonceSize()], encoded[codec.aead.NonceSize():]
+	plain, err := codec.aead.Open(nil, nonce, sealed, nil)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to decrypt: %s", err)
+	}
+
+	return plain, nil
+}
+
+// encodeLine returns line of a JSON lines file encoded by codec as base64, or line itself if codec is nil
+func encodeLine(codec Codec, line []byte) ([]byte, error) {
+	if codec == nil {
+		return line, nil
+	}
+
+	encoded, err := codec.Encode(line)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to encode line: %s", err)
+	}
+
+	return []byte(base64.StdEncoding.EncodeToString(encoded)), nil
+}
+
+// decodeLine reverses encodeLine. With codec plain lines are rejected too,
+// so lines can not be added to a file bypassing codec.
+func decodeLine(codec Codec, line []byte) ([]byte, error) {
+	if codec == nil {
+		return line, nil
+	}
+
+	encoded, err := base64.StdEncoding.DecodeString(string(line))
+	if err != nil {
+		return nil, fmt.Errorf("Failed to decode line: %s", err)
+	}
+
+	plain, err := codec.Decode(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to decode line: %s", err)
+	}
+
+	return plain, nil
+}
@@ -0,0 +1,561 @@
+package slacker
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	mathrand "math/rand"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// The database file is an append-only log with one JSON entry per line.
+// Writers append entries instead of rewriting the whole file, and every put
+// carries the version of the record it replaces, so of two processes changing
+// the same record concurrently only the first appended entry wins and the other
+// one sees a conflict. The log is compacted into one entry per record once it
+// holds many superseded entries.
+
+const (
+	entryPut = "put" // Replaces record of Version-1, or any record if Force
+	entrySet = "set" // Snapshot of record at Version, written by compaction
+
+	compactMinEntries = 1000
+
+	maxLineLength = 64 * 1024 * 1024 // Longest database or event line read
+
+	maxUpdateAttempts = 3 // Bounds retries of record updates lost to other writers
+
+	// Counter updates are retried until written, so counts are not lost to concurrent senders
+	maxCounterAttempts  = 100
+	counterRetryMaxWait = 5 * time.Millisecond
+)
+
+var errConflict = errors.New("Record was changed by another writer")
+
+type dbEntry struct {
+	Op      string    `json:"op"`
+	Hash    string    `json:"hash"`
+	Version int       `json:"version"`
+	Force   bool      `json:"force,omitempty"`
+	Writer  string    `json:"writer,omitempty"`
+	Record  *dbRecord `json:"record"`
+}
+
+// dbRecord holds the state of a message in the database.
+// A record is written as pending before the first delivery, lists recipients
+// as they are delivered and becomes confirmed once all recipients got the message,
+// so an interrupted Send is resumed for the remaining recipients only.
+type dbRecord struct {
+	Message   string    `json:"message"`
+	MessageId string    `json:"message_id,omitempty"`
+	State     string    `json:"state"`
+	Delivered []string  `json:"delivered,omitempty"`
+	Time      time.Time `json:"time"`
+
+	Occurrences []time.Time `json:"occurrences,omitempty"`
+	IncidentId  string      `json:"incident_id,omitempty"`
+	Failed      bool        `json:"failed,omitempty"`
+	DryRun      bool        `json:"dry_run,omitempty"` // Would have been sent, but DryRun was set
+
+	Suppressed      int    `json:"suppressed,omitempty"`
+	FirstSuppressed string `json:"first_suppressed,omitempty"`
+	LastSuppressed  string `json:"last_suppressed,omitempty"`
+
+	LastSeen *time.Time `json:"last_seen,omitempty"`
+	ThreadTs string     `json:"thread_ts,omitempty"`
+	AckedBy  string     `json:"acked_by,omitempty"`
+
+	Digest []digestItem `json:"digest,omitempty"`
+	Bucket *StatsBucket `json:"bucket,omitempty"`
+
+	Version int    `json:"-"` // Number of puts applied to the record
+	writer  string // Writer of the last applied put
+}
+
+// UnmarshalJSON accepts plain message strings written by older versions as confirmed records
+func (record *dbRecord) UnmarshalJSON(data []byte) error {
+	var message string
+	if err := json.Unmarshal(data, &message); err == nil {
+		*record = dbRecord{Message: message, State: recordConfirmed}
+		return nil
+	}
+
+	type plainRecord dbRecord
+	return json.Unmarshal(data, (*plainRecord)(record))
+}
+
+func (record dbRecord) isDelivered(recipient Recipient) bool {
+	for _, id := range record.Delivered {
+		if id == recipient.id() {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (slacker Slacker) getRecord(hash string) (record *dbRecord, found bool, err error) {
+	db, err := slacker.getRecords([]string{hash})
+	if err != nil {
+		return nil, false, fmt.Errorf("Slacker failed to get %s from database: %s", hash, err)
+	}
+
+	if stored, ok := db[hash]; ok == true {
+		return &stored, true, nil
+	}
+
+	return nil, false, nil
+}
+
+// getRecords returns records of hashes found in Store, or all records of the database file
+func (slacker Slacker) getRecords(hashes []string) (db map[string]dbRecord, err error) {
+	if slacker.Store == nil {
+		return slacker.loadDb()
+	}
+
+	if slacker.inMemory(func(memory map[string]dbRecord) {
+		db = make(map[string]dbRecord, len(hashes))
+		for _, hash := range hashes {
+			if record, ok := memory[hash]; ok {
+				db[hash] = record
+			}
+		}
+	}) {
+		return db, nil
+	}
+
+	db, err = slacker.getStoreRecords(hashes)
+	if slacker.shouldFailover(err) {
+		slacker.failover(err)
+		return slacker.getRecords(hashes)
+	}
+
+	return db, err
+}
+
+// putRecord replaces the record of hash if it is still at record.Version
+// and sets record.Version to the new version. It returns errConflict
+// if another writer changed the record since it was read.
+func (slacker Slacker) putRecord(hash string, record *dbRecord) error {
+	conflicts, err := slacker.putRecords(map[string]*dbRecord{hash: record})
+	if err != nil {
+		return fmt.Errorf("Slacker failed to add %s:%s to database: %s", hash, record.Message, err)
+	}
+
+	if conflicts[hash] {
+		return errConflict
+	}
+
+	return nil
+}
+
+// releaseRecord marks a pending record of a failed delivery,
+// so the next Send resumes it without waiting for the pending lease
+func (slacker Slacker) releaseRecord(hash string, record *dbRecord) {
+	if record == nil {
+		return
+	}
+
+	record.Failed = true
+	if err := slacker.putRecord(hash, record); err != nil {
+		slacker.Log.Errorf("Slacker failed to release %s in database: %s", hash, err)
+	}
+}
+
+// updateCounter puts the record of hash returned by update of the current one, nil if not found,
+// and retries on conflicts with other writers after a random wait until it is written.
+// It gives up with errConflict only after maxCounterAttempts or once the context is done.
+func (slacker Slacker) updateCounter(hash string, update func(record *dbRecord) *dbRecord) error {
+	for attempt := 0; attempt < maxCounterAttempts; attempt++ {
+		record, _, err := slacker.getRecord(hash)
+		if err != nil {
+			return err
+		}
+
+		next := update(record)
+		if record != nil {
+			next.Version = record.Version
+		}
+
+		err = slacker.putRecord(hash, next)
+		if err != errConflict {
+			return err
+		}
+
+		select {
+		case <-slacker.context().Done():
+			return slacker.context().Err()
+		case <-time.After(time.Duration(mathrand.Int63n(int64(counterRetryMaxWait)))):
+		}
+	}
+
+	return errConflict
+}
+
+// setRecord replaces the record of hash whatever version it has
+func (slacker Slacker) setRecord(hash string, record dbRecord) error {
+	if slacker.inMemory(func(memory map[string]dbRecord) {
+		record.Version = memory[hash].Version + 1
+		memory[hash] = record
+	}) {
+		return nil
+	}
+
+	var err error
+	if slacker.Store != nil {
+		_, err = slacker.putStoreRecords(map[string]*dbRecord{hash: &record}, true)
+	} else {
+		err = slacker.setRecordFile(hash, record)
+	}
+	if slacker.shouldFailover(err) {
+		slacker.failover(err)
+		return slacker.setRecord(hash, record)
+	}
+
+	return err
+}
+
+func (slacker Slacker) setRecordFile(hash string, record dbRecord) error {
+	entry := dbEntry{Op: entryPut, Hash: hash, Force: true, Record: &record}
+
+	unlock, err := slacker.lockDb()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	if err := slacker.appendEntries([]dbEntry{entry}); err != nil {
+		return fmt.Errorf("Slacker failed to add %s:%s to database: %s", hash, record.Message, err)
+	}
+
+	return nil
+}
+
+// putRecords appends puts of records with a single write and returns hashes
+// of records changed by another writer since they were read
+func (slacker Slacker) putRecords(records map[string]*dbRecord) (conflicts map[string]bool, err error) {
+	if slacker.inMemory(func(memory map[string]dbRecord) {
+		conflicts = putMemoryRecords(memory, records)
+	}) {
+		return conflicts, nil
+	}
+
+	if slacker.Store != nil {
+		conflicts, err = slacker.putStoreRecords(records, false)
+	} else {
+		conflicts, err = slacker.putRecordsFile(records)
+	}
+	if slacker.shouldFailover(err) {
+		slacker.failover(err)
+		return slacker.putRecords(records)
+	}
+
+	return conflicts, err
+}
+
+func (slacker Slacker) putRecordsFile(records map[string]*dbRecord) (conflicts map[string]bool, err error) {
+	writer, err := newWriterId()
+	if err != nil {
+		return nil, err
+	}
+
+	hashes := make([]string, 0, len(records))
+	for hash := range records {
+		hashes = append(hashes, hash)
+	}
+	sort.Strings(hashes)
+
+	entries := make([]dbEntry, 0, len(records))
+	for _, hash := range hashes {
+		record := records[hash]
+		entries = append(entries, dbEntry{Op: entryPut, Hash: hash, Version: record.Version + 1, Writer: writer, Record: record})
+	}
+
+	unlock, err := slacker.lockDb()
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	if err := slacker.appendEntries(entries); err != nil {
+		return nil, err
+	}
+
+	if slacker.ReadOnly {
+		return nil, nil
+	}
+
+	db, count, err := slacker.loadDbLog()
+	if err != nil {
+		return nil, err
+	}
+
+	conflicts = make(map[string]bool)
+	for hash, record := range records {
+		stored, ok := db[hash]
+		if !ok || stored.writer != writer || stored.Version != record.Version+1 {
+			conflicts[hash] = true
+			continue
+		}
+		record.Version = stored.Version
+	}
+
+	if count > compactMinEntries && count > 2*len(db) {
+		if err := slacker.saveDb(db); err != nil {
+			slacker.Log.Errorf("Slacker failed to compact database: %s", err)
+		}
+	}
+
+	return conflicts, nil
+}
+
+// appendEntries, saveDb and loadDbLog expect the caller to hold lockDb
+func (slacker Slacker) appendEntries(entries []dbEntry) error {
+	if err := slacker.context().Err(); err != nil {
+		return err
+	}
+
+	if slacker.ReadOnly {
+		slacker.Log.Debugf("Skip database write of %d entries in read-only mode", len(entries))
+		slacker.emit(EventSkipWrite, "", "", nil)
+		return nil
+	}
+
+	var lines bytes.Buffer
+	for _, entry := range entries {
+		line, err := json.Marshal(entry)
+		if err != nil {
+			return fmt.Errorf("Failed to encode database entry: %s", err)
+		}
+		if line, err = encodeLine(slacker.Codec, line); err != nil {
+			return fmt.Errorf("Failed to encode database entry: %s", err)
+		}
+		lines.Write(line)
+		lines.WriteByte('\n')
+	}
+
+	dbFile, err := os.OpenFile(slacker.DatabaseFilePath, os.O_WRONLY|os.O_APPEND|os.O_CREATE, os.FileMode(0644))
+	if err != nil {
+		return fmt.Errorf("Failed to open database file: %s", err)
+	}
+	defer dbFile.Close()
+
+	// A single write keeps the entries of concurrent writers from interleaving
+	if _, err := dbFile.Write(lines.Bytes()); err != nil {
+		return fmt.Errorf("Failed to append to database file: %s", err)
+	}
+
+	return nil
+}
+
+// saveDb compacts the database file into one entry per record of db
+func (slacker Slacker) saveDb(db map[string]dbRecord) (err error) {
+	if err := slacker.context().Err(); err != nil {
+		return err
+	}
+
+	if slacker.ReadOnly {
+		slacker.Log.Debugf("Skip database write of %d entries in read-only mode", len(db))
+		slacker.emit(EventSkipWrite, "", "", nil)
+		return nil
+	}
+
+	// The database is written to a temporary file renamed over it, so a crash never leaves it half written
+	dbFile, err := ioutil.TempFile(filepath.Dir(slacker.DatabaseFilePath), filepath.Base(slacker.DatabaseFilePath)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("Slacker failed to save database: Failed to create temporary database file: %s", err)
+	}
+	defer func() {
+		if err != nil {
+			dbFile.Close()
+			os.Remove(dbFile.Name())
+		}
+	}()
+
+	hashes := make([]string, 0, len(db))
+	for hash := range db {
+		hashes = append(hashes, hash)
+	}
+	sort.Strings(hashes)
+
+	writer := bufio.NewWriter(dbFile)
+	for _, hash := range hashes {
+		record := db[hash]
+		line, err := json.Marshal(dbEntry{Op: entrySet, Hash: hash, Version: record.Version, Record: &record})
+		if err == nil {
+			line, err = encodeLine(slacker.Codec, line)
+		}
+		if err != nil {
+			return fmt.Errorf("Slacker failed to save database: Failed to encode database entry: %s", err)
+		}
+
+		writer.Write(line)
+		writer.WriteByte('\n')
+	}
+
+	if err = writer.Flush(); err != nil {
+		return fmt.Errorf("Slacker failed to save database: Failed to write database file: %s", err)
+	}
+	if err = dbFile.Chmod(os.FileMode(0644)); err != nil {
+		return fmt.Errorf("Slacker failed to save database: Failed to set database file mode: %s", err)
+	}
+	if err = dbFile.Sync(); err != nil {
+		return fmt.Errorf("Slacker failed to save database: Failed to sync database file: %s", err)
+	}
+	if err = dbFile.Close(); err != nil {
+		return fmt.Errorf("Slacker failed to save database: Failed to close database file: %s", err)
+	}
+	if err = os.Rename(dbFile.Name(), slacker.DatabaseFilePath); err != nil {
+		return fmt.Errorf("Slacker failed to save database: Failed to replace database file: %s", err)
+	}
+
+	syncDir(filepath.Dir(slacker.DatabaseFilePath))
+	return nil
+}
+
+// syncDir flushes the rename of a file in dir to disk where the platform supports it
+func syncDir(dir string) {
+	if dirFile, err := os.Open(dir); err == nil {
+		dirFile.Sync()
+		dirFile.Close()
+	}
+}
+
+func (slacker Slacker) loadDb() (db map[string]dbRecord, err error) {
+	if slacker.inMemory(func(memory map[string]dbRecord) {
+		db = make(map[string]dbRecord, len(memory))
+		for hash, record := range memory {
+			db[hash] = record
+		}
+	}) {
+		return db, nil
+	}
+
+	db, err = slacker.loadDbFile()
+	if slacker.shouldFailover(err) {
+		slacker.failover(err)
+		return slacker.loadDb()
+	}
+
+	return db, err
+}
+
+func (slacker Slacker) loadDbFile() (db map[string]dbRecord, err error) {
+	unlock, err := slacker.lockDb()
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	db, _, err = slacker.loadDbLog()
+	return
+}
+
+// loadDbLog replays the database file and returns records with the number of entries read
+func (slacker Slacker) loadDbLog() (db map[string]dbRecord, count int, err error) {
+	if err := slacker.context().Err(); err != nil {
+		return nil, 0, err
+	}
+
+	db = make(map[string]dbRecord)
+
+	dbFile, err := os.Open(slacker.DatabaseFilePath)
+	if os.IsNotExist(err) {
+		return db, 0, nil
+	}
+	if err != nil {
+		return nil, 0, fmt.Errorf("Slacker failed to load database: %s", err)
+	}
+	defer dbFile.Close()
+
+	scanner := bufio.NewScanner(dbFile)
+	scanner.Buffer(make([]byte, 64*1024), maxLineLength)
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		count++
+
+		line, err := decodeLine(slacker.Codec, line)
+		if err != nil {
+			slacker.Log.Errorf("Skip database entry failed to decode: %s", err)
+			continue
+		}
+
+		var entry dbEntry
+		if err := json.Unmarshal(line, &entry); err != nil || entry.Op == "" || entry.Record == nil {
+			if legacy, ok := decodeLegacyDb(line); ok {
+				for hash, record := range legacy {
+					record.Version = 1
+					db[hash] = record
+				}
+				continue
+			}
+
+			// A torn write of a crashed process, later entries are still valid
+			slacker.Log.Errorf("Skip corrupted database entry: %s", truncate(string(line), maxDiagnosticsLength))
+			continue
+		}
+
+		applyEntry(db, entry)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, 0, fmt.Errorf("Slacker failed to load database: %s", err)
+	}
+
+	return db, count, nil
+}
+
+func applyEntry(db map[string]dbRecord, entry dbEntry) {
+	record := *entry.Record
+	record.writer = entry.Writer
+
+	if entry.Op == entrySet {
+		record.Version = entry.Version
+		db[entry.Hash] = record
+		return
+	}
+
+	current := db[entry.Hash]
+	if !entry.Force && entry.Version != current.Version+1 {
+		// Stale put of a writer which lost the race
+		return
+	}
+
+	record.Version = current.Version + 1
+	db[entry.Hash] = record
+}
+
+// decodeLegacyDb decodes the single JSON object database written by older versions
+func decodeLegacyDb(line []byte) (map[string]dbRecord, bool) {
+	if !strings.HasPrefix(string(line), "{") {
+		return nil, false
+	}
+
+	var legacy map[string]dbRecord
+	if err := json.Unmarshal(line, &legacy); err != nil {
+		return nil, false
+	}
+
+	return legacy, true
+}
+
+func newWriterId() (string, error) {
+	id := make([]byte, 8)
+	if _, err := rand.Read(id); err != nil {
+		return "", fmt.Errorf("Failed to generate writer id: %s", err)
+	}
+
+	return hex.EncodeToString(id), nil
+}
@@ -0,0 +1,59 @@
+package slacker
+
+import "time"
+
+// pendingLease is how long a pending record is owned by the Send which wrote it
+const pendingLease = 5 * time.Minute
+
+// State is the deduplication state used by ShouldSend.
+// The zero value is an empty state.
+type State struct {
+	records map[string]dbRecord
+}
+
+// ShouldSend decides whether message is let through by Frequency and Alignment of Slacker at now
+// and returns the state after sending it. It reads no files and no clock,
+// so the decision can be reasoned about and property tested apart from Send.
+// WarmUp is not considered since it depends on the process start time.
+func (slacker Slacker) ShouldSend(state State, message Message, now time.Time) (bool, State) {
+	if slacker.isAlways() {
+		return true, state
+	}
+
+	if message.MessageTag != "" {
+		slacker.MessageTag = message.MessageTag
+	}
+	if slacker.MessageTag == "" {
+		slacker.MessageTag = DefaultMessageTag
+	}
+
+	hash := slacker.getHashAt(now)
+	record, found := state.records[hash]
+	if !slacker.isDue(record, found, now) {
+		return false, state
+	}
+
+	next := State{records: make(map[string]dbRecord, len(state.records)+1)}
+	for key, value := range state.records {
+		next.records[key] = value
+	}
+	next.records[hash] = dbRecord{Message: message.Text, State: recordConfirmed, Time: now}
+
+	return true, next
+}
+
+// isDue reports whether a message with a record found in the database should be sent at now.
+// Pending records are due, so an interrupted delivery is resumed.
+func (slacker Slacker) isDue(record dbRecord, found bool, now time.Time) bool {
+	if !found || record.State != recordConfirmed {
+		return true
+	}
+
+	return slacker.isExpired(record, now)
+}
+
+// isInFlight reports whether a pending record is being delivered by another Send right now.
+// Pending records of failed or crashed deliveries are not in flight and get resumed.
+func isInFlight(record dbRecord, now time.Time) bool {
+	return !record.Failed && now.Sub(record.Time) < pendingLease
+}
@@ -0,0 +1,46 @@
+package slacker
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// defaultSlacker is the Slacker of package level Notify
+var defaultSlacker = struct {
+	sync.RWMutex
+	slacker    Slacker
+	configured bool
+}{}
+
+// Configure sets the Slacker used by package level Notify, so scripts don't need
+// to pass a Slacker around. It can be called again to change settings.
+func Configure(slacker Slacker) error {
+	if err := slacker.setDefaults(); err != nil {
+		return fmt.Errorf("Slacker failed to configure: %s", err)
+	}
+
+	defaultSlacker.Lock()
+	defaultSlacker.slacker = slacker
+	defaultSlacker.configured = true
+	defaultSlacker.Unlock()
+
+	return nil
+}
+
+// Notify sends message tagged with tag by the Slacker set with Configure
+func Notify(tag string, message string) error {
+	defaultSlacker.RLock()
+	slacker, configured := defaultSlacker.slacker, defaultSlacker.configured
+	defaultSlacker.RUnlock()
+
+	if !configured {
+		return errors.New("Slacker is not configured, call Configure first")
+	}
+
+	if tag != "" {
+		slacker.MessageTag = tag
+	}
+
+	return slacker.Send(message)
+}
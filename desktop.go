@@ -0,0 +1,59 @@
+package slacker
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"time"
+)
+
+// desktopTimeout bounds showing a desktop notification, a stuck notifier is killed after it
+const desktopTimeout = 10 * time.Second
+
+// windowsAppId is the registered AUMID of PowerShell, toasts of unregistered ids are dropped silently
+const windowsAppId = `{1AC14E77-02E7-4E5D-B744-2EB1AE5198B7}\WindowsPowerShell\v1.0\powershell.exe`
+
+// windowsToastScript shows a toast with title and message passed in environment variables
+const windowsToastScript = `
+[Windows.UI.Notifications.ToastNotificationManager, Windows.UI.Notifications, ContentType = WindowsRuntime] > $null
+$template = [Windows.UI.Notifications.ToastNotificationManager]::GetTemplateContent([Windows.UI.Notifications.ToastTemplateType]::ToastText02)
+$texts = $template.GetElementsByTagName('text')
+$texts.Item(0).AppendChild($template.CreateTextNode($env:SLACKER_TITLE)) > $null
+$texts.Item(1).AppendChild($template.CreateTextNode($env:SLACKER_MESSAGE)) > $null
+$toast = [Windows.UI.Notifications.ToastNotification]::new($template)
+[Windows.UI.Notifications.ToastNotificationManager]::CreateToastNotifier($env:SLACKER_APP_ID).Show($toast)
+`
+
+// mirrorToDesktop shows message as a local desktop notification in background:
+// a toast on Windows, Notification Center on macOS and libnotify elsewhere
+func (slacker Slacker) mirrorToDesktop(message string) {
+	go slacker.showOnDesktop(message)
+}
+
+func (slacker Slacker) showOnDesktop(message string) {
+	title := slacker.From + ": " + slacker.MessageTag
+
+	ctx, cancel := context.WithTimeout(context.Background(), desktopTimeout)
+	defer cancel()
+
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "windows":
+		cmd = exec.CommandContext(ctx, "powershell", "-NoProfile", "-NonInteractive", "-Command", windowsToastScript)
+		cmd.Env = append(os.Environ(), "SLACKER_APP_ID="+windowsAppId, "SLACKER_TITLE="+title, "SLACKER_MESSAGE="+message)
+	case "darwin":
+		cmd = exec.CommandContext(ctx, "osascript",
+			"-e", "on run argv",
+			"-e", "display notification (item 2 of argv) with title (item 1 of argv)",
+			"-e", "end run",
+			title, message)
+	default:
+		cmd = exec.CommandContext(ctx, "notify-send", title, message)
+	}
+
+	if output, err := cmd.CombinedOutput(); err != nil {
+		slacker.Log.Errorf("Slacker failed to mirror message to desktop: %s", fmt.Sprintf("%s %s", err, output))
+	}
+}
@@ -0,0 +1,46 @@
+package slacker
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+)
+
+func TestDesktopMirrorDoesNotBlockSend(t *testing.T) {
+	if runtime.GOOS == "windows" || runtime.GOOS == "darwin" {
+		t.Skip("Fake notifier is a shell script")
+	}
+
+	bin := t.TempDir()
+	marker := filepath.Join(bin, "shown")
+	script := "#!/bin/sh\ntouch " + marker + "\nsleep 3\n"
+	if err := ioutil.WriteFile(filepath.Join(bin, "notify-send"), []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", bin+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	slacker := newTestSlacker(t, newTestHook(t))
+	slacker.MirrorToDesktop = true
+
+	started := time.Now()
+	if err := slacker.Send("shown"); err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(started); elapsed > 2*time.Second {
+		t.Fatalf("Send waited %s for the desktop notification", elapsed)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		if _, err := os.Stat(marker); err == nil {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("Desktop notification is not shown")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
@@ -0,0 +1,148 @@
+package slacker
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sync/atomic"
+	"time"
+)
+
+// IP versions used to connect to Slack
+const (
+	IPAny int = 0
+	IPv4  int = 4
+	IPv6  int = 6
+)
+
+const (
+	defaultDialTimeout = 10 * time.Second
+	minDialTimeout     = 2 * time.Second // Least time to connect to one of several addresses
+)
+
+// dialer returns the dialer of Slacker connections with DialTimeout
+func (slacker *Slacker) dialer() *net.Dialer {
+	dialTimeout := slacker.DialTimeout
+	if dialTimeout <= 0 {
+		dialTimeout = defaultDialTimeout
+	}
+
+	return &net.Dialer{
+		Timeout:   dialTimeout,
+		KeepAlive: 10 * time.Second,
+		Resolver:  slacker.resolver(),
+	}
+}
+
+// dialContext connects with resolver settings of Slacker. Without ResolveTimeout
+// the host name is dialed as is, so IPv4 and IPv6 addresses are raced like by the default dialer.
+// With it the host is resolved first and its addresses are tried one by one,
+// each within its share of DialTimeout.
+func (slacker *Slacker) dialContext(ctx context.Context, network string, address string) (net.Conn, error) {
+	dialer := slacker.dialer()
+	network = slacker.ipNetwork(network)
+	if slacker.ResolveTimeout <= 0 {
+		return dialer.DialContext(ctx, network, address)
+	}
+
+	host, port, err := net.SplitHostPort(address)
+	if err != nil {
+		return nil, err
+	}
+
+	ips, err := slacker.resolve(ctx, host)
+	if err != nil {
+		return nil, err
+	}
+
+	deadline := time.Now().Add(dialer.Timeout)
+	var lastErr error
+	for i, ip := range ips {
+		dialer.Timeout = time.Until(deadline) / time.Duration(len(ips)-i)
+		if dialer.Timeout < minDialTimeout && time.Until(deadline) > minDialTimeout {
+			dialer.Timeout = minDialTimeout
+		}
+
+		conn, err := dialer.DialContext(ctx, network, net.JoinHostPort(ip.String(), port))
+		if err == nil {
+			return conn, nil
+		}
+		lastErr = err
+	}
+
+	return nil, lastErr
+}
+
+// hasResolverSettings reports whether connections need dialContext instead of the default dialer
+func (slacker *Slacker) hasResolverSettings() bool {
+	return len(slacker.DNSServers) > 0 || slacker.IPVersion != IPAny || slacker.ResolveTimeout > 0
+}
+
+func (slacker *Slacker) resolve(ctx context.Context, host string) ([]net.IP, error) {
+	if ip := net.ParseIP(host); ip != nil {
+		return []net.IP{ip}, nil
+	}
+
+	resolveTimeout := slacker.ResolveTimeout
+	if resolveTimeout <= 0 {
+		resolveTimeout = defaultDialTimeout
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, resolveTimeout)
+	defer cancel()
+
+	lookup := "ip"
+	if slacker.IPVersion == IPv4 {
+		lookup = "ip4"
+	}
+	if slacker.IPVersion == IPv6 {
+		lookup = "ip6"
+	}
+
+	ips, err := slacker.resolver().LookupIP(ctx, lookup, host)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(ips) == 0 {
+		return nil, errors.New("No addresses found for " + host)
+	}
+
+	return ips, nil
+}
+
+// resolver returns the system resolver or a resolver querying DNSServers in turn
+func (slacker *Slacker) resolver() *net.Resolver {
+	if len(slacker.DNSServers) == 0 {
+		return net.DefaultResolver
+	}
+
+	servers := make([]string, len(slacker.DNSServers))
+	for i, server := range slacker.DNSServers {
+		if _, _, err := net.SplitHostPort(server); err != nil {
+			server = net.JoinHostPort(server, "53")
+		}
+		servers[i] = server
+	}
+
+	var next uint32
+	return &net.Resolver{
+		PreferGo: true,
+		Dial: func(ctx context.Context, network string, _ string) (net.Conn, error) {
+			server := servers[int(atomic.AddUint32(&next, 1)-1)%len(servers)]
+			return (&net.Dialer{}).DialContext(ctx, network, server)
+		},
+	}
+}
+
+func (slacker *Slacker) ipNetwork(network string) string {
+	if slacker.IPVersion == IPv4 {
+		return "tcp4"
+	}
+
+	if slacker.IPVersion == IPv6 {
+		return "tcp6"
+	}
+
+	return network
+}
@@ -0,0 +1,37 @@
+package slacker
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestDialSettings(t *testing.T) {
+	hook := newTestHook(t)
+	hookUrl, _ := url.Parse(hook.URL)
+
+	for name, configure := range map[string]func(slacker *Slacker){
+		"default":         func(slacker *Slacker) {},
+		"ip version":      func(slacker *Slacker) { slacker.IPVersion = IPv4 },
+		"resolve timeout": func(slacker *Slacker) { slacker.ResolveTimeout = time.Second },
+	} {
+		slacker := newTestSlacker(t, hook)
+		slacker.Hook = "http://localhost:" + hookUrl.Port()
+		slacker.Frequency = NotifyAlways
+		configure(&slacker)
+
+		if err := slacker.Send(name); err != nil {
+			t.Fatalf("Send with %s dial settings failed: %s", name, err)
+		}
+	}
+}
+
+func TestNoProxyByDefault(t *testing.T) {
+	slacker := newTestSlacker(t, newTestHook(t))
+	slacker.setHttpClient()
+
+	if slacker.httpClient.Transport.(*http.Transport).Proxy != nil {
+		t.Fatal("Proxy is used without Proxy set")
+	}
+}
@@ -0,0 +1,222 @@
+package slacker
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// digestItem is a distinct message text collected for a digest
+type digestItem struct {
+	Text  string `json:"text"`
+	Count int    `json:"count"`
+}
+
+// digestHash is the database key of messages collected for the digest of the tag,
+// its record Time is when the first of them was collected
+func (slacker Slacker) digestHash() string {
+	return "digest:" + slacker.MessageTag
+}
+
+// sendDigest collects message and sends the digest if DigestWindow is over at now
+func (slacker Slacker) sendDigest(message string, now time.Time) (id string, err error) {
+	due, err := slacker.addToDigest(message, now)
+	if err != nil {
+		slacker.Log.Errorf("Slacker failed to send message: %s", err)
+		slacker.emit(EventError, slacker.digestHash(), message, err)
+		return "", err
+	}
+
+	if due == nil {
+		slacker.Log.Debugf("Add message %s to digest: %s", slacker.MessageTag, message)
+		slacker.emit(EventSuppress, slacker.digestHash(), message, nil)
+		return "", nil
+	}
+
+	return slacker.messageId, slacker.sendDue(*due)
+}
+
+// sendDue delivers the digest of due messages and puts them back into the digest of the tag
+// if it fails, so they are sent with the next digest instead of being lost
+func (slacker Slacker) sendDue(due dbRecord) error {
+	err := slacker.deliver(digestText(due), "", nil)
+	if err == nil {
+		return nil
+	}
+
+	restoreErr := slacker.updateCounter(slacker.digestHash(), func(record *dbRecord) *dbRecord {
+		next := &dbRecord{State: recordConfirmed, Time: due.Time, Digest: append([]digestItem(nil), due.Digest...)}
+		if record != nil && len(record.Digest) > 0 {
+			next.Digest = mergeDigestItems(next.Digest, record.Digest)
+			if record.Time.Before(next.Time) {
+				next.Time = record.Time
+			}
+		}
+		return next
+	})
+	if restoreErr != nil {
+		slacker.Log.Errorf("Slacker failed to keep %d undelivered digest messages of %s: %s", len(due.Digest), slacker.MessageTag, restoreErr)
+	}
+
+	return err
+}
+
+// addToDigest collects message at now and returns the collected messages
+// with message added if DigestWindow is over, so the next message starts a new digest
+func (slacker Slacker) addToDigest(message string, now time.Time) (due *dbRecord, err error) {
+	hash := slacker.digestHash()
+	for attempt := 0; attempt < maxUpdateAttempts; attempt++ {
+		record, found, err := slacker.getRecord(hash)
+		if err != nil {
+			return nil, err
+		}
+
+		next := &dbRecord{State: recordConfirmed, Time: now}
+		if found {
+			next.Version = record.Version
+			if len(record.Digest) > 0 {
+				next.Time = record.Time
+				next.Digest = append([]digestItem(nil), record.Digest...)
+			}
+		}
+		next.Digest = addDigestItem(next.Digest, message)
+
+		due = nil
+		if !now.Before(next.Time.Add(slacker.DigestWindow)) {
+			due = &dbRecord{Time: next.Time, Digest: next.Digest}
+			next.Digest = nil
+		}
+
+		err = slacker.putRecord(hash, next)
+		if err == errConflict {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		return due, nil
+	}
+
+	return nil, fmt.Errorf("Slacker failed to add message to digest %s: %s", hash, errConflict)
+}
+
+// FlushDigest sends messages collected for the digest of MessageTag without waiting for DigestWindow,
+// e.g. before a process exits
+func (slacker Slacker) FlushDigest() error {
+	if err := slacker.setDefaults(); err != nil {
+		return fmt.Errorf("Slacker failed to send message: %s", err)
+	}
+
+	return slacker.flushDigest(time.Time{})
+}
+
+// StartDigestFlush sends the digest of MessageTag in background every interval once DigestWindow is over,
+// so messages collected before the tag goes silent are not kept until its next message.
+// Call the returned stop function to end it.
+func (slacker Slacker) StartDigestFlush(interval time.Duration) (stop func(), err error) {
+	if err := slacker.setDefaults(); err != nil {
+		return nil, fmt.Errorf("Slacker failed to start digest flush: %s", err)
+	}
+
+	done := make(chan struct{})
+	ticker := time.NewTicker(interval)
+
+	go func() {
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case now := <-ticker.C:
+				if err := slacker.flushDigest(now); err != nil {
+					slacker.Log.Errorf("Slacker failed to flush digest %s: %s", slacker.MessageTag, err)
+				}
+			}
+		}
+	}()
+
+	return func() { close(done) }, nil
+}
+
+// flushDigest sends collected messages if DigestWindow is over at now, or right away if now is zero
+func (slacker Slacker) flushDigest(now time.Time) error {
+	slacker.ctx = context.Background()
+	slacker.messageId = slacker.newMessageId()
+
+	hash := slacker.digestHash()
+	for attempt := 0; attempt < maxUpdateAttempts; attempt++ {
+		record, found, err := slacker.getRecord(hash)
+		if err != nil {
+			return fmt.Errorf("Slacker failed to send digest: %s", err)
+		}
+		if !found || len(record.Digest) == 0 {
+			return nil
+		}
+		if !now.IsZero() && now.Before(record.Time.Add(slacker.DigestWindow)) {
+			return nil
+		}
+
+		err = slacker.putRecord(hash, &dbRecord{State: recordConfirmed, Time: time.Now(), Version: record.Version})
+		if err == errConflict {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("Slacker failed to send digest: %s", err)
+		}
+
+		return slacker.sendDue(*record)
+	}
+
+	return nil
+}
+
+// addDigestItem counts message in items, repeated texts are listed once
+func addDigestItem(items []digestItem, message string) []digestItem {
+	for i := range items {
+		if items[i].Text == message {
+			items[i].Count++
+			return items
+		}
+	}
+
+	return append(items, digestItem{Text: message, Count: 1})
+}
+
+// mergeDigestItems adds counts of more to items
+func mergeDigestItems(items []digestItem, more []digestItem) []digestItem {
+	for _, item := range more {
+		i := 0
+		for i < len(items) && items[i].Text != item.Text {
+			i++
+		}
+		if i == len(items) {
+			items = append(items, digestItem{Text: item.Text})
+		}
+		items[i].Count += item.Count
+	}
+
+	return items
+}
+
+// digestText lists collected messages of record with their counts
+func digestText(record dbRecord) string {
+	total := 0
+	lines := make([]string, 0, len(record.Digest))
+	for _, item := range record.Digest {
+		total += item.Count
+		if item.Count > 1 {
+			lines = append(lines, fmt.Sprintf("• %s (x%d)", item.Text, item.Count))
+		} else {
+			lines = append(lines, "• "+item.Text)
+		}
+	}
+
+	noun := "messages"
+	if total == 1 {
+		noun = "message"
+	}
+
+	return fmt.Sprintf("%d %s since %s:\n%s", total, noun, record.Time.Format("15:04"), strings.Join(lines, "\n"))
+}
@@ -0,0 +1,53 @@
+package slacker
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFailedDigestIsKept(t *testing.T) {
+	hook := newTestHook(t)
+	slacker := newTestSlacker(t, hook)
+	slacker.DigestWindow = time.Hour
+
+	if err := slacker.Send("collected"); err != nil {
+		t.Fatal(err)
+	}
+
+	hook.setFail(true)
+	if err := slacker.FlushDigest(); err == nil {
+		t.Fatal("Failed digest returns no error")
+	}
+
+	hook.setFail(false)
+	if err := slacker.FlushDigest(); err != nil {
+		t.Fatal(err)
+	}
+	if len(hook.posted("collected")) != 1 {
+		t.Fatalf("Digest is lost after a failed delivery: %v", hook.payloads)
+	}
+}
+
+func TestDigestFlushSendsSilentTag(t *testing.T) {
+	hook := newTestHook(t)
+	slacker := newTestSlacker(t, hook)
+	slacker.DigestWindow = 20 * time.Millisecond
+
+	if err := slacker.Send("burst"); err != nil {
+		t.Fatal(err)
+	}
+
+	stop, err := slacker.StartDigestFlush(5 * time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stop()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for len(hook.posted("burst")) == 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("Digest of a silent tag is not sent")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
@@ -0,0 +1,44 @@
+package slacker
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// checkEgress returns an error if AllowedHosts is set and the host of rawUrl is not in it.
+// An allowed host "*.example.com" matches any subdomain of example.com.
+func (slacker Slacker) checkEgress(rawUrl string) error {
+	if len(slacker.AllowedHosts) == 0 {
+		return nil
+	}
+
+	parsed, err := url.Parse(rawUrl)
+	if err != nil {
+		return fmt.Errorf("Failed to parse url: %s", err)
+	}
+
+	host := strings.ToLower(parsed.Hostname())
+	for _, allowed := range slacker.AllowedHosts {
+		allowed = strings.ToLower(allowed)
+		if host == allowed {
+			return nil
+		}
+
+		if strings.HasPrefix(allowed, "*.") && strings.HasSuffix(host, allowed[1:]) {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("Host %q is not in allowed hosts %v", host, slacker.AllowedHosts)
+}
+
+// checkRedirect keeps redirects within AllowedHosts
+func (slacker *Slacker) checkRedirect(request *http.Request, via []*http.Request) error {
+	if len(via) >= 10 {
+		return fmt.Errorf("Stopped after %d redirects", len(via))
+	}
+
+	return slacker.checkEgress(request.URL.String())
+}
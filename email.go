@@ -0,0 +1,48 @@
+package slacker
+
+import (
+	"net"
+	"net/smtp"
+	"strconv"
+	"strings"
+)
+
+// EmailSink sends messages as plain text emails over SMTP.
+// STARTTLS is used when the server supports it.
+type EmailSink struct {
+	Host     string
+	Port     int
+	Username string // Optional, no authentication if empty
+	Password string
+	From     string
+	To       []string
+}
+
+// Payload implements PayloadRenderer, it is the email with headers
+func (sink EmailSink) Payload(message Message) ([]byte, error) {
+	headers := []string{
+		"From: " + sink.From,
+		"To: " + strings.Join(sink.To, ", "),
+		"Subject: " + message.MessageTag,
+		"MIME-Version: 1.0",
+		"Content-Type: text/plain; charset=UTF-8",
+	}
+	body := strings.Join(headers, "\r\n") + "\r\n\r\n" + strings.Replace(message.Text, "\n", "\r\n", -1)
+
+	return []byte(body), nil
+}
+
+// Notify implements Notifier
+func (sink EmailSink) Notify(message Message) error {
+	var auth smtp.Auth
+	if sink.Username != "" {
+		auth = smtp.PlainAuth("", sink.Username, sink.Password, sink.Host)
+	}
+
+	body, err := sink.Payload(message)
+	if err != nil {
+		return err
+	}
+
+	return smtp.SendMail(net.JoinHostPort(sink.Host, strconv.Itoa(sink.Port)), auth, sink.From, sink.To, body)
+}
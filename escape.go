@@ -0,0 +1,21 @@
+package slacker
+
+import "strings"
+
+// slackEscaper escapes the characters Slack reserves for links, mentions and dates
+var slackEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+
+// Escape returns text with &, < and > escaped, so Slack shows it as is
+// instead of reading links or mentions in it, e.g. "<none>" or "a<b>c"
+func Escape(text string) string {
+	return slackEscaper.Replace(text)
+}
+
+// escape returns message escaped for Slack if EscapeText is set
+func (slacker Slacker) escape(message string) string {
+	if !slacker.EscapeText {
+		return message
+	}
+
+	return Escape(message)
+}
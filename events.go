@@ -0,0 +1,113 @@
+package slacker
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// Event types
+const (
+	EventSend        string = "send"        // Message was delivered
+	EventSuppress    string = "suppress"    // Message was skipped by Frequency or WarmUp
+	EventError       string = "error"       // Message failed to be delivered or recorded
+	EventEscalate    string = "escalate"    // Message was replaced by an escalation, e.g. a crash loop alert
+	EventSkipWrite   string = "skip_write"  // Database write was skipped in read-only mode
+	EventFailover    string = "failover"    // Database failed and records are kept in memory, see MemoryFailover
+	EventResync      string = "resync"      // Database recovered and records kept in memory were written back
+	EventSlow        string = "slow"        // Posts to the service in Text are slow, see SlowSendThreshold
+	EventAcknowledge string = "acknowledge" // Alert of the tag was acknowledged by who is in Text
+)
+
+// Event is a structured record of a decision Slacker made about a message
+type Event struct {
+	Type       string    `json:"type"`
+	MessageTag string    `json:"message_tag"`
+	Hash       string    `json:"hash,omitempty"`
+	MessageId  string    `json:"message_id,omitempty"`
+	Text       string    `json:"text"`
+	Error      string    `json:"error,omitempty"`
+	Time       time.Time `json:"time"`
+	DryRun     bool      `json:"dry_run,omitempty"` // Sent with DryRun, so never posted
+}
+
+// EventSink publishes events, e.g. to a file, NATS or Kafka
+type EventSink interface {
+	Emit(event Event) error
+}
+
+// FileEventSink appends events to a file as JSON lines
+type FileEventSink struct {
+	Path  string
+	Codec Codec // Optional, encodes every line, Replay with the same Codec set on Slacker
+	mutex sync.Mutex
+}
+
+// NewFileEventSink returns FileEventSink appending to file path
+func NewFileEventSink(path string) *FileEventSink {
+	return &FileEventSink{Path: path}
+}
+
+// Emit implements EventSink
+func (sink *FileEventSink) Emit(event Event) error {
+	line, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("Failed to encode event: %s", err)
+	}
+	if line, err = encodeLine(sink.Codec, line); err != nil {
+		return fmt.Errorf("Failed to encode event: %s", err)
+	}
+
+	sink.mutex.Lock()
+	defer sink.mutex.Unlock()
+
+	file, err := os.OpenFile(sink.Path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, os.FileMode(0644))
+	if err != nil {
+		return fmt.Errorf("Failed to open events file: %s", err)
+	}
+	defer file.Close()
+
+	if _, err := file.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("Failed to write events file: %s", err)
+	}
+
+	return nil
+}
+
+// emit sends event to Events sink if it is set, counts sent and suppressed messages for Stats
+// and records them to the history of Store
+func (slacker Slacker) emit(eventType string, hash string, text string, err error) {
+	// Replayed messages were counted, recorded and emitted when they were sent
+	if slacker.replaying {
+		return
+	}
+
+	if slacker.Statistics && (eventType == EventSend || eventType == EventSuppress) {
+		slacker.recordStats(eventType == EventSend, time.Now())
+	}
+
+	slacker.recordHistory(eventType, hash, text, err, time.Now())
+
+	if slacker.Events == nil {
+		return
+	}
+
+	event := Event{
+		Type:       eventType,
+		MessageTag: slacker.MessageTag,
+		Hash:       hash,
+		MessageId:  slacker.messageId,
+		Text:       text,
+		Time:       time.Now(),
+		DryRun:     slacker.DryRun,
+	}
+	if err != nil {
+		event.Error = err.Error()
+	}
+
+	if err := slacker.Events.Emit(event); err != nil {
+		slacker.Log.Errorf("Slacker failed to emit %s event: %s", eventType, err)
+	}
+}
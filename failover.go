@@ -0,0 +1,201 @@
+package slacker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+	"time"
+)
+
+// storeRecheckInterval is how often a failed database is checked for recovery
+const storeRecheckInterval = 30 * time.Second
+
+// dbFailover is the state of a database file with MemoryFailover
+type dbFailover struct {
+	records   map[string]dbRecord // Records kept in memory, nil while the database works
+	checked   time.Time           // Last recovery check
+	failovers int64
+	resyncs   int64
+}
+
+// failovers holds failover states by failoverKey
+var failovers = struct {
+	sync.Mutex
+	byKey map[interface{}]*dbFailover
+}{byKey: make(map[interface{}]*dbFailover)}
+
+// StoreStats describes the database health with MemoryFailover
+type StoreStats struct {
+	InMemory  bool  // Records are kept in memory since the database failed
+	Failovers int64 // Number of switches to memory since process start
+	Resyncs   int64 // Number of recoveries since process start
+}
+
+// GetStoreStats returns the health of Store, or of DatabaseFilePath without it, within this process
+func (slacker Slacker) GetStoreStats() StoreStats {
+	if slacker.DatabaseFilePath == "" {
+		slacker.DatabaseFilePath = DefaultDatabaseFilePath
+	}
+
+	failovers.Lock()
+	defer failovers.Unlock()
+
+	state, ok := failovers.byKey[slacker.failoverKey()]
+	if !ok {
+		return StoreStats{}
+	}
+
+	return StoreStats{InMemory: state.records != nil, Failovers: state.failovers, Resyncs: state.resyncs}
+}
+
+// failoverKey identifies the database: Store if set, so Slackers sharing it fail over together,
+// DatabaseFilePath otherwise or if Store can not be a map key
+func (slacker Slacker) failoverKey() interface{} {
+	if slacker.Store != nil && reflect.TypeOf(slacker.Store).Comparable() {
+		return slacker.Store
+	}
+
+	return slacker.DatabaseFilePath
+}
+
+// shouldFailover reports whether err of a database operation is a failure of the database itself
+func (slacker Slacker) shouldFailover(err error) bool {
+	if !slacker.MemoryFailover || err == nil || err == errConflict {
+		return false
+	}
+
+	return !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded)
+}
+
+// failover switches the database to memory after err, starting with records still readable from the file,
+// and sends a warning about it
+func (slacker Slacker) failover(err error) {
+	records := make(map[string]dbRecord)
+	if slacker.Store == nil {
+		if loaded, loadErr := slacker.loadDbFile(); loadErr == nil {
+			records = loaded
+		}
+	}
+
+	failovers.Lock()
+	state, ok := failovers.byKey[slacker.failoverKey()]
+	if !ok {
+		state = &dbFailover{}
+		failovers.byKey[slacker.failoverKey()] = state
+	}
+	switched := state.records == nil
+	if switched {
+		state.records = records
+		state.checked = time.Now()
+		state.failovers++
+	}
+	failovers.Unlock()
+
+	if !switched {
+		return
+	}
+
+	slacker.Log.Errorf("Slacker failed to use %s, records are kept in memory until it recovers: %s", slacker.databaseName(), err)
+	slacker.emit(EventFailover, "", "", err)
+
+	// The warning is sent by itself, not as a part of the message being sent
+	warning := slacker
+	warning.blocks = nil
+	warning.attachments = nil
+	warning.results = nil
+	warning.resolving = false
+	if err := warning.deliver(fmt.Sprintf("Warning: %s failed, messages are deduplicated in memory of this process until it recovers: %s", slacker.databaseName(), err), "", nil); err != nil {
+		slacker.Log.Errorf("Slacker failed to send failover warning: %s", err)
+	}
+}
+
+// databaseName names Store or DatabaseFilePath in logs and warnings
+func (slacker Slacker) databaseName() string {
+	if slacker.Store != nil {
+		return fmt.Sprintf("store %T", slacker.Store)
+	}
+
+	return "database " + slacker.DatabaseFilePath
+}
+
+// inMemory applies operation to records kept in memory and returns true if the database failed.
+// Once in storeRecheckInterval it writes the records back to the database instead and returns false if it recovered.
+func (slacker Slacker) inMemory(operation func(memory map[string]dbRecord)) bool {
+	if !slacker.MemoryFailover {
+		return false
+	}
+
+	failovers.Lock()
+	defer failovers.Unlock()
+
+	state, ok := failovers.byKey[slacker.failoverKey()]
+	if !ok || state.records == nil {
+		return false
+	}
+
+	if time.Since(state.checked) >= storeRecheckInterval {
+		state.checked = time.Now()
+		if err := slacker.resync(state.records); err == nil {
+			slacker.Log.Infof("Recovered %s, %d records kept in memory are written back", slacker.databaseName(), len(state.records))
+			slacker.emit(EventResync, "", "", nil)
+			state.records = nil
+			state.resyncs++
+			return false
+		}
+	}
+
+	operation(state.records)
+	return true
+}
+
+// resync writes records kept in memory over the records of the database file or Store
+func (slacker Slacker) resync(records map[string]dbRecord) error {
+	if slacker.Store != nil {
+		forced := make(map[string]*dbRecord, len(records))
+		for hash, record := range records {
+			record := record
+			forced[hash] = &record
+		}
+
+		_, err := slacker.putStoreRecords(forced, true)
+		return err
+	}
+
+	unlock, err := slacker.lockDb()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	db, _, err := slacker.loadDbLog()
+	if err != nil {
+		return err
+	}
+
+	for hash, record := range records {
+		if stored, ok := db[hash]; ok && stored.Version > record.Version {
+			record.Version = stored.Version
+		}
+		db[hash] = record
+	}
+
+	return slacker.saveDb(db)
+}
+
+// putMemoryRecords stores records in memory like putRecords does in the database file
+func putMemoryRecords(memory map[string]dbRecord, records map[string]*dbRecord) (conflicts map[string]bool) {
+	conflicts = make(map[string]bool)
+	for hash, record := range records {
+		if memory[hash].Version != record.Version {
+			conflicts[hash] = true
+			continue
+		}
+
+		record.Version++
+		memory[hash] = *record
+	}
+
+	return conflicts
+}
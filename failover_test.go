@@ -0,0 +1,45 @@
+package slacker
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+)
+
+// brokenStore fails every operation
+type brokenStore struct{}
+
+func (store *brokenStore) Get(key string) (StoreRecord, bool, error) {
+	return StoreRecord{}, false, errors.New("store is down")
+}
+
+func (store *brokenStore) Put(records []StoreRecord) (map[string]bool, error) {
+	return nil, errors.New("store is down")
+}
+
+func TestFailoverIsKeyedByStore(t *testing.T) {
+	hook := newTestHook(t)
+	store := &brokenStore{}
+
+	slacker := newTestSlacker(t, hook)
+	slacker.Store = store
+	slacker.MemoryFailover = true
+	if err := slacker.Send("first alert"); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(hook.posted("first alert")) != 1 {
+		t.Fatalf("Message is not sent while the store is down: %v", hook.payloads)
+	}
+	if len(hook.posted("Warning: store *slacker.brokenStore failed")) != 1 {
+		t.Fatalf("Failover warning is not sent: %v", hook.payloads)
+	}
+
+	other := newTestSlacker(t, hook)
+	other.Store = store
+	other.MemoryFailover = true
+	other.DatabaseFilePath = filepath.Join(t.TempDir(), "other.json")
+	if stats := other.GetStoreStats(); !stats.InMemory || stats.Failovers != 1 {
+		t.Fatalf("Slacker sharing the store does not share its failover: %+v", stats)
+	}
+}
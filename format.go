@@ -0,0 +1,78 @@
+package slacker
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Slack date format tokens for FormatDate
+const (
+	SlackDateNum         string = "{date_num}"          // 2014-02-18
+	SlackDate            string = "{date}"              // February 18th, 2014
+	SlackDateShort       string = "{date_short}"        // Feb 18, 2014
+	SlackDateLong        string = "{date_long}"         // Tuesday, February 18th, 2014
+	SlackDatePretty      string = "{date_pretty}"       // Today, Yesterday or SlackDate
+	SlackDateShortPretty string = "{date_short_pretty}" // Today, Yesterday or SlackDateShort
+	SlackDateLongPretty  string = "{date_long_pretty}"  // Today, Yesterday or SlackDateLong
+	SlackTime            string = "{time}"              // 6:39 AM
+	SlackTimeSecs        string = "{time_secs}"         // 6:39:42 AM
+)
+
+// FormatDate returns Slack date formatting for t, so every reader sees it in their own time zone.
+// Format is text with the SlackDate* and SlackTime* tokens, e.g. SlackDateShortPretty + " at " + SlackTime.
+// Clients unable to render it show t in UTC.
+func FormatDate(t time.Time, format string) string {
+	fallback := t.UTC().Format("2006-01-02 15:04:05 UTC")
+	return fmt.Sprintf("<!date^%d^%s|%s>", t.Unix(), format, fallback)
+}
+
+// numberSeparators holds thousands and decimal separators by language
+var numberSeparators = map[string][2]string{
+	"en": {",", "."},
+	"de": {".", ","},
+	"es": {".", ","},
+	"it": {".", ","},
+	"nl": {".", ","},
+	"pt": {".", ","},
+	"fr": {" ", ","},
+	"ru": {" ", ","},
+	"pl": {" ", ","},
+	"sv": {" ", ","},
+}
+
+// FormatNumber formats n with decimals digits after the decimal separator
+// and grouped thousands using separators of locale, e.g. "de" or "fr-CA".
+// Unknown locales are formatted as "en".
+func FormatNumber(n float64, decimals int, locale string) string {
+	language := strings.ToLower(strings.SplitN(strings.Replace(locale, "_", "-", 1), "-", 2)[0])
+	separators, ok := numberSeparators[language]
+	if !ok {
+		separators = numberSeparators["en"]
+	}
+
+	formatted := strconv.FormatFloat(math.Abs(n), 'f', decimals, 64)
+	integer, fraction := formatted, ""
+	if dot := strings.IndexByte(formatted, '.'); dot >= 0 {
+		integer, fraction = formatted[:dot], formatted[dot+1:]
+	}
+
+	var grouped []string
+	for len(integer) > 3 {
+		grouped = append([]string{integer[len(integer)-3:]}, grouped...)
+		integer = integer[:len(integer)-3]
+	}
+	grouped = append([]string{integer}, grouped...)
+
+	result := strings.Join(grouped, separators[0])
+	if fraction != "" {
+		result += separators[1] + fraction
+	}
+	if n < 0 && strings.Trim(formatted, "0.") != "" {
+		result = "-" + result
+	}
+
+	return result
+}
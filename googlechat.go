@@ -0,0 +1,81 @@
+package slacker
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+)
+
+// GoogleChatSink posts messages to a Google Chat space webhook as cards v2
+type GoogleChatSink struct {
+	Url        string       // Incoming webhook url of the space
+	HttpClient *http.Client // Optional
+}
+
+type googleChatMessage struct {
+	CardsV2 []googleChatCardWithId `json:"cardsV2"`
+}
+
+type googleChatCardWithId struct {
+	CardId string         `json:"cardId"`
+	Card   googleChatCard `json:"card"`
+}
+
+type googleChatCard struct {
+	Header   googleChatCardHeader `json:"header"`
+	Sections []googleChatSection  `json:"sections"`
+}
+
+type googleChatCardHeader struct {
+	Title string `json:"title"`
+}
+
+type googleChatSection struct {
+	Widgets []googleChatWidget `json:"widgets"`
+}
+
+type googleChatWidget struct {
+	TextParagraph googleChatTextParagraph `json:"textParagraph"`
+}
+
+type googleChatTextParagraph struct {
+	Text string `json:"text"`
+}
+
+// Payload implements PayloadRenderer
+func (sink GoogleChatSink) Payload(message Message) ([]byte, error) {
+	return json.Marshal(googleChatMessage{
+		CardsV2: []googleChatCardWithId{{
+			CardId: message.MessageTag,
+			Card: googleChatCard{
+				Header: googleChatCardHeader{Title: message.MessageTag},
+				Sections: []googleChatSection{{
+					Widgets: []googleChatWidget{{TextParagraph: googleChatTextParagraph{Text: message.Text}}},
+				}},
+			},
+		}},
+	})
+}
+
+// Notify implements Notifier
+func (sink GoogleChatSink) Notify(message Message) error {
+	return sink.NotifyContext(context.Background(), message)
+}
+
+// NotifyContext implements ContextNotifier
+func (sink GoogleChatSink) NotifyContext(ctx context.Context, message Message) error {
+	payload, err := sink.Payload(message)
+	if err != nil {
+		return err
+	}
+
+	request, err := http.NewRequest(http.MethodPost, sink.Url, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Content-Type", "application/json; charset=UTF-8")
+
+	_, err = doSinkRequest(ctx, sink.HttpClient, "Google Chat", request)
+	return err
+}
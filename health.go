@@ -0,0 +1,46 @@
+package slacker
+
+import "time"
+
+const (
+	DefaultHealthyIconEmoji   string = ":large_green_circle:"
+	DefaultUnhealthyIconEmoji string = ":red_circle:"
+
+	healthHash string = "health"
+)
+
+// healthIcon returns the icon emoji reflecting sender health:
+// the result of HealthFunc if set, otherwise the result of the last post to Slack
+func (slacker Slacker) healthIcon() string {
+	healthy := true
+	if slacker.HealthFunc != nil {
+		healthy = slacker.HealthFunc()
+	} else if record, found, err := slacker.getRecord(healthHash); err != nil {
+		slacker.Log.Errorf("Slacker failed to get last send result: %s", err)
+	} else if found {
+		healthy = !record.Failed
+	}
+
+	if healthy {
+		return slacker.HealthyIconEmoji
+	}
+
+	return slacker.UnhealthyIconEmoji
+}
+
+// recordHealth stores the result of the last post to Slack for healthIcon
+func (slacker Slacker) recordHealth(err error) {
+	if !slacker.HealthIcons || slacker.HealthFunc != nil {
+		return
+	}
+
+	record := dbRecord{Message: "ok", State: recordConfirmed, Time: time.Now()}
+	if err != nil {
+		record.Message = err.Error()
+		record.Failed = true
+	}
+
+	if err := slacker.setRecord(healthHash, record); err != nil {
+		slacker.Log.Errorf("Slacker failed to record send result: %s", err)
+	}
+}
@@ -0,0 +1,48 @@
+package slacker
+
+import "time"
+
+// HistoryRecord is a sent, suppressed or failed message kept by a HistoryRecorder
+type HistoryRecord struct {
+	MessageTag string
+	Hash       string
+	MessageId  string
+	Text       string
+	Time       time.Time
+	Recipients []string
+	Status     string // EventSend, EventSuppress or EventError
+	Error      string
+}
+
+// HistoryRecorder is implemented by stores keeping the history of messages, e.g. for audits.
+// Slacker records every sent, suppressed and failed message to its Store if it is a HistoryRecorder.
+type HistoryRecorder interface {
+	RecordHistory(record HistoryRecord) error
+}
+
+// recordHistory adds an event of type eventType to the history of Store
+func (slacker Slacker) recordHistory(eventType string, hash string, text string, err error, now time.Time) {
+	recorder, ok := slacker.Store.(HistoryRecorder)
+	if !ok || (eventType != EventSend && eventType != EventSuppress && eventType != EventError) {
+		return
+	}
+
+	record := HistoryRecord{
+		MessageTag: slacker.MessageTag,
+		Hash:       hash,
+		MessageId:  slacker.messageId,
+		Text:       text,
+		Time:       now,
+		Status:     eventType,
+	}
+	for _, recipient := range slacker.To {
+		record.Recipients = append(record.Recipients, recipient.id())
+	}
+	if err != nil {
+		record.Error = err.Error()
+	}
+
+	if err := recorder.RecordHistory(record); err != nil {
+		slacker.Log.Errorf("Slacker failed to record %s history: %s", eventType, err)
+	}
+}
@@ -0,0 +1,41 @@
+package slacker
+
+import "strings"
+
+// With returns a child Slacker with the settings of slacker and labels added to its messages,
+// e.g. slacker.With("request_id", id, "user", name) appends " [request_id=... user=...]".
+// Labels do not change deduplication, the child shares MessageTag with slacker.
+func (slacker Slacker) With(keysAndValues ...string) Slacker {
+	labels := make([]string, 0, len(slacker.Labels)+len(keysAndValues)/2+1)
+	labels = append(labels, slacker.Labels...)
+	for i := 0; i < len(keysAndValues); i += 2 {
+		if i+1 == len(keysAndValues) {
+			labels = append(labels, keysAndValues[i])
+			break
+		}
+		labels = append(labels, keysAndValues[i]+"="+keysAndValues[i+1])
+	}
+
+	slacker.Labels = labels
+	return slacker
+}
+
+// WithTagPrefix returns a child Slacker with prefix added to its MessageTag,
+// so messages of the child are deduplicated apart from messages of slacker
+func (slacker Slacker) WithTagPrefix(prefix string) Slacker {
+	if slacker.MessageTag == "" {
+		slacker.MessageTag = DefaultMessageTag
+	}
+
+	slacker.MessageTag = prefix + slacker.MessageTag
+	return slacker
+}
+
+// labeled appends Labels to message
+func (slacker Slacker) labeled(message string) string {
+	if len(slacker.Labels) == 0 {
+		return message
+	}
+
+	return message + " [" + strings.Join(slacker.Labels, " ") + "]"
+}
@@ -0,0 +1,140 @@
+package slacker
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	DefaultSlowSendPeriod time.Duration = 5 * time.Minute
+
+	// maxLatencySamples bounds recent post latencies kept per service for p95
+	maxLatencySamples = 500
+)
+
+// latencyBounds are upper bounds of latency histogram buckets
+var latencyBounds = []time.Duration{
+	50 * time.Millisecond,
+	100 * time.Millisecond,
+	250 * time.Millisecond,
+	500 * time.Millisecond,
+	time.Second,
+	2500 * time.Millisecond,
+	5 * time.Second,
+	10 * time.Second,
+}
+
+// LatencyStats is the histogram of post latencies to a service since process start
+type LatencyStats struct {
+	Bounds  []time.Duration // Upper bounds of Buckets
+	Buckets []int64         // Number of posts by latency, the last one counts posts slower than all Bounds
+	Count   int64
+	Sum     time.Duration
+	P95     time.Duration // Of posts within the last SlowSendPeriod, or DefaultSlowSendPeriod
+}
+
+type latencySample struct {
+	time    time.Time
+	latency time.Duration
+}
+
+type latencyTracker struct {
+	buckets   []int64
+	count     int64
+	sum       time.Duration
+	recent    []latencySample
+	slowSince time.Time // Since when p95 exceeds SlowSendThreshold
+	warned    time.Time
+}
+
+// latencies holds post latency trackers by service, e.g. "Slack", "Twilio" or "TelegramSink"
+var latencies = struct {
+	sync.Mutex
+	byService map[string]*latencyTracker
+}{byService: make(map[string]*latencyTracker)}
+
+// GetLatencyStats returns post latency histograms by service
+func GetLatencyStats() map[string]LatencyStats {
+	latencies.Lock()
+	defer latencies.Unlock()
+
+	stats := make(map[string]LatencyStats, len(latencies.byService))
+	for service, tracker := range latencies.byService {
+		stats[service] = LatencyStats{
+			Bounds:  latencyBounds,
+			Buckets: append([]int64(nil), tracker.buckets...),
+			Count:   tracker.count,
+			Sum:     tracker.sum,
+			P95:     tracker.p95(),
+		}
+	}
+
+	return stats
+}
+
+// observeLatency records latency of a post to service and warns once per SlowSendPeriod
+// while p95 of posts within the period stays above SlowSendThreshold for the whole period
+func (slacker Slacker) observeLatency(service string, latency time.Duration) {
+	period := slacker.SlowSendPeriod
+	if period <= 0 {
+		period = DefaultSlowSendPeriod
+	}
+
+	now := time.Now()
+	service = strings.TrimPrefix(service, "slacker.")
+
+	latencies.Lock()
+	tracker, ok := latencies.byService[service]
+	if !ok {
+		tracker = &latencyTracker{buckets: make([]int64, len(latencyBounds)+1)}
+		latencies.byService[service] = tracker
+	}
+
+	bucket := sort.Search(len(latencyBounds), func(i int) bool { return latency <= latencyBounds[i] })
+	tracker.buckets[bucket]++
+	tracker.count++
+	tracker.sum += latency
+
+	tracker.recent = append(tracker.recent, latencySample{time: now, latency: latency})
+	start := 0
+	for start < len(tracker.recent) && (now.Sub(tracker.recent[start].time) > period || len(tracker.recent)-start > maxLatencySamples) {
+		start++
+	}
+	tracker.recent = tracker.recent[start:]
+
+	p95 := tracker.p95()
+	warn := false
+	if slacker.SlowSendThreshold <= 0 || p95 <= slacker.SlowSendThreshold {
+		tracker.slowSince = time.Time{}
+	} else {
+		if tracker.slowSince.IsZero() {
+			tracker.slowSince = now
+		}
+		if now.Sub(tracker.slowSince) >= period && now.Sub(tracker.warned) >= period {
+			tracker.warned = now
+			warn = true
+		}
+	}
+	latencies.Unlock()
+
+	if warn {
+		slacker.Log.Errorf("Warning: p95 latency of posts to %s is %s, above %s for %s", service, p95, slacker.SlowSendThreshold, period)
+		slacker.emit(EventSlow, "", service, nil)
+	}
+}
+
+func (tracker *latencyTracker) p95() time.Duration {
+	if len(tracker.recent) == 0 {
+		return 0
+	}
+
+	sorted := make([]time.Duration, len(tracker.recent))
+	for i, sample := range tracker.recent {
+		sorted[i] = sample.latency
+	}
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	return sorted[(len(sorted)*95-1)/100]
+}
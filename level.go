@@ -0,0 +1,82 @@
+package slacker
+
+import (
+	"fmt"
+	"time"
+)
+
+// Level is the severity of a message sent with SendWithLevel
+type Level int
+
+const (
+	LevelDebug Level = iota
+	LevelInfo
+	LevelWarn
+	LevelError
+	LevelCritical
+)
+
+var levelNames = map[Level]string{
+	LevelDebug:    "debug",
+	LevelInfo:     "info",
+	LevelWarn:     "warn",
+	LevelError:    "error",
+	LevelCritical: "critical",
+}
+
+func (level Level) String() string {
+	if name, ok := levelNames[level]; ok {
+		return name
+	}
+
+	return fmt.Sprintf("level%d", int(level))
+}
+
+// LevelConfig replaces settings of Slacker for messages of a level, empty fields keep them
+type LevelConfig struct {
+	IconEmoji string
+	Color     string      // Optional, messages are sent as an attachment with the color bar, e.g. ColorDanger or "#439FE0"
+	To        []Recipient // Optional, e.g. the on-call channel for LevelCritical
+
+	// Frequency and FrequencyDuration replace the ones of Slacker if either is set,
+	// Always lets every message of the level through
+	Frequency         int
+	FrequencyDuration time.Duration
+	Always            bool
+}
+
+// SendWithLevel sends message like Send with settings of level from Levels.
+// Messages of every level are deduplicated apart, their MessageTag gets the level as suffix, e.g. "db:critical".
+func (slacker Slacker) SendWithLevel(level Level, message string) error {
+	if slacker.MessageTag == "" {
+		slacker.MessageTag = DefaultMessageTag
+	}
+	slacker.MessageTag += ":" + level.String()
+
+	config, ok := slacker.Levels[level]
+	if !ok {
+		return slacker.Send(message)
+	}
+
+	if config.IconEmoji != "" {
+		slacker.IconEmoji = config.IconEmoji
+	}
+
+	if len(config.To) > 0 {
+		slacker.To = config.To
+	}
+
+	if config.Always {
+		slacker.Frequency = NotifyAlways
+		slacker.FrequencyDuration = 0
+	} else if config.Frequency != NotifyAlways || config.FrequencyDuration > 0 {
+		slacker.Frequency = config.Frequency
+		slacker.FrequencyDuration = config.FrequencyDuration
+	}
+
+	if config.Color != "" {
+		return slacker.SendAttachment(Attachment{Color: config.Color, Text: message, Fallback: message})
+	}
+
+	return slacker.Send(message)
+}
@@ -0,0 +1,143 @@
+package slacker
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// Slack payload limits, see https://api.slack.com/reference/block-kit/blocks
+const (
+	slackMaxTextLength        = 40000
+	slackMaxBlocks            = 50
+	slackMaxAttachments       = 100
+	slackMaxBlockIdLength     = 255
+	slackMaxHeaderLength      = 150
+	slackMaxSectionLength     = 3000
+	slackMaxSectionFields     = 10
+	slackMaxFieldLength       = 2000
+	slackMaxActionElements    = 25
+	slackMaxButtonTextLength  = 75
+	slackMaxButtonUrlLength   = 3000
+	slackMaxButtonValueLength = 2000
+	slackMaxActionIdLength    = 255
+)
+
+// Linter is implemented by sinks which check a message against their limits before it is sent
+type Linter interface {
+	Lint(message Message) error
+}
+
+// LintError lists every problem found in a payload for Service
+type LintError struct {
+	Service  string
+	Problems []string
+}
+
+func (err *LintError) Error() string {
+	return fmt.Sprintf("Payload for %s is invalid: %s", err.Service, strings.Join(err.Problems, "; "))
+}
+
+// lint collects problems of a payload for service
+type lint struct {
+	service  string
+	problems []string
+}
+
+func (l *lint) problem(format string, args ...interface{}) {
+	l.problems = append(l.problems, fmt.Sprintf(format, args...))
+}
+
+// length adds a problem if text of what is longer than limit characters
+func (l *lint) length(what string, text string, limit int) {
+	if length := utf8.RuneCountInString(text); length > limit {
+		l.problem("%s is %d characters long, limit is %d", what, length, limit)
+	}
+}
+
+// count adds a problem if there are more than limit items of what
+func (l *lint) count(what string, count int, limit int) {
+	if count > limit {
+		l.problem("%d %s, limit is %d", count, what, limit)
+	}
+}
+
+func (l *lint) err() error {
+	if len(l.problems) == 0 {
+		return nil
+	}
+
+	return &LintError{Service: l.service, Problems: l.problems}
+}
+
+// LintSlackMessage checks message against Slack limits on text, blocks and attachments.
+// Slacker checks every message before it is posted, so Slack does not reject it with a vague invalid_blocks.
+func LintSlackMessage(message SlackMessage) error {
+	l := &lint{service: "Slack"}
+
+	l.length("text", message.Text, slackMaxTextLength)
+	if message.IconEmoji != "" && message.IconUrl != "" {
+		l.problem("icon_emoji and icon_url are both set, Slack ignores icon_url")
+	}
+	l.count("blocks", len(message.Blocks), slackMaxBlocks)
+	l.count("attachments", len(message.Attachments), slackMaxAttachments)
+
+	for i, block := range message.Blocks {
+		what := fmt.Sprintf("block %d (%s)", i+1, block.Type)
+		l.length(what+" block_id", block.BlockId, slackMaxBlockIdLength)
+
+		switch block.Type {
+		case BlockHeader:
+			if block.Text == nil || block.Text.Type != TextPlain {
+				l.problem("%s needs plain_text text", what)
+			} else {
+				l.length(what+" text", block.Text.Text, slackMaxHeaderLength)
+			}
+		case BlockSection:
+			if block.Text == nil && len(block.Fields) == 0 {
+				l.problem("%s needs text or fields", what)
+			}
+			if block.Text != nil {
+				l.length(what+" text", block.Text.Text, slackMaxSectionLength)
+			}
+			l.count(what+" fields", len(block.Fields), slackMaxSectionFields)
+			for j, field := range block.Fields {
+				l.length(fmt.Sprintf("%s field %d", what, j+1), field.Text, slackMaxFieldLength)
+			}
+		case BlockActions:
+			if len(block.Elements) == 0 {
+				l.problem("%s needs elements", what)
+			}
+			l.count(what+" elements", len(block.Elements), slackMaxActionElements)
+		}
+
+		elements := block.Elements
+		if block.Accessory != nil {
+			elements = append([]BlockElement{*block.Accessory}, elements...)
+		}
+		for j, element := range elements {
+			lintBlockElement(l, fmt.Sprintf("%s element %d", what, j+1), element)
+		}
+	}
+
+	return l.err()
+}
+
+func lintBlockElement(l *lint, what string, element BlockElement) {
+	if element.Text != nil {
+		l.length(what+" text", element.Text.Text, slackMaxButtonTextLength)
+	}
+	l.length(what+" url", element.Url, slackMaxButtonUrlLength)
+	l.length(what+" value", element.Value, slackMaxButtonValueLength)
+	l.length(what+" action_id", element.ActionId, slackMaxActionIdLength)
+}
+
+// lintSink checks message against the limits of sink if it is a Linter
+func lintSink(sink Notifier, message Message) error {
+	linter, ok := sink.(Linter)
+	if !ok {
+		return nil
+	}
+
+	return linter.Lint(message)
+}
@@ -0,0 +1,60 @@
+package slacker
+
+import (
+	"fmt"
+	"os"
+	"sync"
+)
+
+// dbMutexes serializes database access of goroutines by database file path,
+// file locks do the same for processes
+var (
+	dbMutexes      = make(map[string]*sync.Mutex)
+	dbMutexesGuard sync.Mutex
+)
+
+func dbMutex(path string) *sync.Mutex {
+	dbMutexesGuard.Lock()
+	defer dbMutexesGuard.Unlock()
+
+	mutex, ok := dbMutexes[path]
+	if !ok {
+		mutex = &sync.Mutex{}
+		dbMutexes[path] = mutex
+	}
+
+	return mutex
+}
+
+// lockDb takes the exclusive lock of the database: a mutex within the process
+// and an advisory lock of the ".lock" file next to the database between processes.
+// In read-only mode the lock file is not created, so only the mutex is taken.
+func (slacker Slacker) lockDb() (unlock func(), err error) {
+	mutex := dbMutex(slacker.DatabaseFilePath)
+	mutex.Lock()
+
+	if slacker.ReadOnly {
+		return mutex.Unlock, nil
+	}
+
+	lockFilePath := slacker.DatabaseFilePath + ".lock"
+	file, err := os.OpenFile(lockFilePath, os.O_RDWR|os.O_CREATE, os.FileMode(0644))
+	if err != nil {
+		mutex.Unlock()
+		return nil, fmt.Errorf("Failed to open database lock file %s: %s", lockFilePath, err)
+	}
+
+	if err := lockFile(file); err != nil {
+		file.Close()
+		mutex.Unlock()
+		return nil, fmt.Errorf("Failed to lock database lock file %s: %s", lockFilePath, err)
+	}
+
+	return func() {
+		if err := unlockFile(file); err != nil {
+			slacker.Log.Errorf("Failed to unlock database lock file %s: %s", lockFilePath, err)
+		}
+		slacker.ioClose(file)
+		mutex.Unlock()
+	}, nil
+}
@@ -0,0 +1,15 @@
+//go:build !(linux || darwin || freebsd || netbsd || openbsd || dragonfly || illumos) && !windows
+
+package slacker
+
+import "os"
+
+// lockFile is a no-op where advisory file locks are not available,
+// database access is still serialized within the process
+func lockFile(file *os.File) error {
+	return nil
+}
+
+func unlockFile(file *os.File) error {
+	return nil
+}
@@ -0,0 +1,45 @@
+package slacker
+
+import (
+	"log"
+	"os"
+)
+
+// Logger receives Slacker internal logs.
+// *logrus.Logger, *logrus.Entry and *zap.SugaredLogger implement it as is,
+// *log.Logger and *slog.Logger are wrapped with NewStdLogger and NewSlogLogger.
+type Logger interface {
+	Debugf(format string, args ...interface{})
+	Infof(format string, args ...interface{})
+	Errorf(format string, args ...interface{})
+}
+
+// defaultLogger writes to stdout prefixed with DefaultUsername
+func defaultLogger() Logger {
+	return NewStdLogger(log.New(os.Stdout, DefaultUsername+" ", log.LstdFlags))
+}
+
+// StdLogger is Logger writing every level to a *log.Logger
+type StdLogger struct {
+	Log *log.Logger
+}
+
+// NewStdLogger returns Logger writing to logger
+func NewStdLogger(logger *log.Logger) StdLogger {
+	return StdLogger{Log: logger}
+}
+
+// Debugf implements Logger
+func (logger StdLogger) Debugf(format string, args ...interface{}) {
+	logger.Log.Printf(format, args...)
+}
+
+// Infof implements Logger
+func (logger StdLogger) Infof(format string, args ...interface{}) {
+	logger.Log.Printf(format, args...)
+}
+
+// Errorf implements Logger
+func (logger StdLogger) Errorf(format string, args ...interface{}) {
+	logger.Log.Printf(format, args...)
+}
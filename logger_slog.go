@@ -0,0 +1,42 @@
+//go:build go1.21
+
+package slacker
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+)
+
+// SlogLogger is Logger writing to a *slog.Logger, e.g. with a JSON handler and fields added by With
+type SlogLogger struct {
+	Log *slog.Logger
+}
+
+// NewSlogLogger returns Logger writing to logger with attribute component=slacker
+func NewSlogLogger(logger *slog.Logger) SlogLogger {
+	return SlogLogger{Log: logger.With("component", "slacker")}
+}
+
+// Debugf implements Logger
+func (logger SlogLogger) Debugf(format string, args ...interface{}) {
+	logger.log(slog.LevelDebug, format, args)
+}
+
+// Infof implements Logger
+func (logger SlogLogger) Infof(format string, args ...interface{}) {
+	logger.log(slog.LevelInfo, format, args)
+}
+
+// Errorf implements Logger
+func (logger SlogLogger) Errorf(format string, args ...interface{}) {
+	logger.log(slog.LevelError, format, args)
+}
+
+func (logger SlogLogger) log(level slog.Level, format string, args []interface{}) {
+	if !logger.Log.Enabled(context.Background(), level) {
+		return
+	}
+
+	logger.Log.Log(context.Background(), level, fmt.Sprintf(format, args...))
+}
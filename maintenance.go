@@ -0,0 +1,157 @@
+package slacker
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+// MaintenanceReport describes a database maintenance run
+type MaintenanceReport struct {
+	Pruned         int   // Number of expired entries removed
+	ReclaimedBytes int64 // Database file size decrease
+	Err            error
+}
+
+// StartMaintenance prunes expired window entries from the database and compacts the file
+// every interval in background. If report is not nil it is called after every run.
+// Call the returned stop function to end maintenance.
+func (slacker Slacker) StartMaintenance(interval time.Duration, report func(MaintenanceReport)) (stop func(), err error) {
+	if err := slacker.setDefaults(); err != nil {
+		return nil, fmt.Errorf("Slacker failed to start maintenance: %s", err)
+	}
+
+	done := make(chan struct{})
+	ticker := time.NewTicker(interval)
+
+	go func() {
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case now := <-ticker.C:
+				result := slacker.maintain(now)
+				if result.Err != nil {
+					slacker.Log.Errorf("Slacker failed to maintain database: %s", result.Err)
+				} else {
+					slacker.Log.Infof("Database maintenance pruned %d entries, reclaimed %d bytes", result.Pruned, result.ReclaimedBytes)
+				}
+
+				if report != nil {
+					report(result)
+				}
+			}
+		}
+	}()
+
+	return func() { close(done) }, nil
+}
+
+func (slacker Slacker) maintain(now time.Time) (report MaintenanceReport) {
+	sizeBefore := slacker.dbSize()
+
+	report.Pruned, report.Err = slacker.prune(now, true)
+	if report.Err != nil {
+		return
+	}
+
+	report.ReclaimedBytes = sizeBefore - slacker.dbSize()
+	return
+}
+
+// PruneExpired removes entries of calendar windows which are already over from the database
+// and returns the number of removed entries. The file is not rewritten if none expired.
+func (slacker Slacker) PruneExpired() (pruned int, err error) {
+	if err := slacker.setDefaults(); err != nil {
+		return 0, fmt.Errorf("Slacker failed to prune database: %s", err)
+	}
+
+	return slacker.prune(time.Now(), false)
+}
+
+// prune removes entries of calendar windows which are over at now
+// and rewrites the database file without them, or also compacts it when none expired if compact
+func (slacker Slacker) prune(now time.Time, compact bool) (pruned int, err error) {
+	if slacker.inMemory(func(memory map[string]dbRecord) {
+		pruned = pruneExpired(memory, now)
+	}) {
+		return pruned, nil
+	}
+
+	// Stores remove expired records themselves
+	if slacker.Store != nil {
+		return 0, nil
+	}
+
+	unlock, err := slacker.lockDb()
+	if err != nil {
+		return 0, err
+	}
+	defer unlock()
+
+	db, _, err := slacker.loadDbLog()
+	if err != nil {
+		return 0, err
+	}
+
+	pruned = pruneExpired(db, now)
+	if pruned == 0 && !compact {
+		return 0, nil
+	}
+
+	if err := slacker.saveDb(db); err != nil {
+		return 0, err
+	}
+
+	return pruned, nil
+}
+
+// pruneExpired deletes records of calendar windows which are over at now from db
+func pruneExpired(db map[string]dbRecord, now time.Time) (pruned int) {
+	for hash := range db {
+		if windowEnd, ok := hashWindowEnd(hash); ok && !now.Before(windowEnd) {
+			delete(db, hash)
+			pruned++
+		}
+	}
+
+	return pruned
+}
+
+// hashWindowEnd returns the end of the calendar window embedded in hash by getHashAt
+func hashWindowEnd(hash string) (time.Time, bool) {
+	colon := strings.Index(hash, ":")
+	if colon < 0 {
+		return time.Time{}, false
+	}
+
+	if slash := strings.Index(hash[:colon], "/"); slash > 0 {
+		start, err := time.Parse("2006-01-02T150405Z", hash[:slash])
+		window, durationErr := time.ParseDuration(hash[slash+1 : colon])
+		if err == nil && durationErr == nil {
+			return start.Add(window), true
+		}
+		return time.Time{}, false
+	}
+
+	if start, err := time.Parse("2006-01-02-15", hash[:colon]); err == nil {
+		return start.Add(time.Hour), true
+	}
+
+	if start, err := time.Parse("2006-01-02", hash[:colon]); err == nil {
+		return start.Add(24 * time.Hour), true
+	}
+
+	return time.Time{}, false
+}
+
+func (slacker Slacker) dbSize() int64 {
+	info, err := os.Stat(slacker.DatabaseFilePath)
+	if err != nil {
+		return 0
+	}
+
+	return info.Size()
+}
@@ -0,0 +1,55 @@
+package slacker
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// MatrixSink posts messages to a Matrix room with an access token
+type MatrixSink struct {
+	Homeserver  string // e.g. https://matrix.example.org
+	AccessToken string
+	RoomId      string       // e.g. !abcdef:example.org
+	HttpClient  *http.Client // Optional
+}
+
+type matrixMessage struct {
+	MsgType string `json:"msgtype"`
+	Body    string `json:"body"`
+}
+
+// Payload implements PayloadRenderer
+func (sink MatrixSink) Payload(message Message) ([]byte, error) {
+	return json.Marshal(matrixMessage{MsgType: "m.text", Body: message.Text})
+}
+
+// Notify implements Notifier
+func (sink MatrixSink) Notify(message Message) error {
+	return sink.NotifyContext(context.Background(), message)
+}
+
+// NotifyContext implements ContextNotifier
+func (sink MatrixSink) NotifyContext(ctx context.Context, message Message) error {
+	payload, err := sink.Payload(message)
+	if err != nil {
+		return err
+	}
+
+	// Transaction id makes retries of the same request idempotent on the homeserver
+	apiUrl := strings.TrimRight(sink.Homeserver, "/") + "/_matrix/client/v3/rooms/" +
+		url.PathEscape(sink.RoomId) + "/send/m.room.message/" + url.PathEscape(NewUlid())
+
+	request, err := http.NewRequest(http.MethodPut, apiUrl, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Authorization", "Bearer "+sink.AccessToken)
+	request.Header.Set("Content-Type", "application/json")
+
+	_, err = doSinkRequest(ctx, sink.HttpClient, "Matrix", request)
+	return err
+}
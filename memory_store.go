@@ -0,0 +1,75 @@
+package slacker
+
+import (
+	"sync"
+	"time"
+)
+
+// memoryStoreSweepInterval is how often Put removes expired records of MemoryStore
+const memoryStoreSweepInterval = time.Minute
+
+// MemoryStore is a Store keeping records in memory of the process,
+// for long running processes which need no deduplication across restarts
+type MemoryStore struct {
+	mutex   sync.Mutex
+	records map[string]StoreRecord
+	swept   time.Time
+}
+
+// NewMemoryStore returns an empty MemoryStore
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{records: make(map[string]StoreRecord)}
+}
+
+// Get implements Store
+func (store *MemoryStore) Get(key string) (record StoreRecord, found bool, err error) {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	record, found = store.records[key]
+	if found && isStoreRecordExpired(record, time.Now()) {
+		delete(store.records, key)
+		return StoreRecord{}, false, nil
+	}
+
+	return record, found, nil
+}
+
+// Put implements Store
+func (store *MemoryStore) Put(records []StoreRecord) (conflicts map[string]bool, err error) {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	now := time.Now()
+	if now.Sub(store.swept) >= memoryStoreSweepInterval {
+		for key, record := range store.records {
+			if isStoreRecordExpired(record, now) {
+				delete(store.records, key)
+			}
+		}
+		store.swept = now
+	}
+
+	conflicts = make(map[string]bool)
+	for _, record := range records {
+		stored, found := store.records[record.Key]
+		if found && isStoreRecordExpired(stored, now) {
+			stored, found = StoreRecord{}, false
+		}
+
+		if !record.Force && stored.Version != record.Version {
+			conflicts[record.Key] = true
+			continue
+		}
+
+		record.Version = stored.Version + 1
+		record.Force = false
+		store.records[record.Key] = record
+	}
+
+	return conflicts, nil
+}
+
+func isStoreRecordExpired(record StoreRecord, now time.Time) bool {
+	return !record.Expires.IsZero() && !now.Before(record.Expires)
+}
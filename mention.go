@@ -0,0 +1,39 @@
+package slacker
+
+import "strings"
+
+// mentionId returns id without a leading @ and with control characters escaped,
+// so a mistyped id shows up as text instead of breaking the message
+func mentionId(id string) string {
+	return Escape(strings.TrimPrefix(strings.TrimSpace(id), "@"))
+}
+
+// MentionUser returns a mention of the user with id, e.g. U0123ABC, which notifies the user
+func MentionUser(id string) string {
+	return "<@" + mentionId(id) + ">"
+}
+
+// MentionGroup returns a mention of the user group with id, e.g. S0123ABC, which notifies its members
+func MentionGroup(id string) string {
+	return "<!subteam^" + mentionId(id) + ">"
+}
+
+// MentionChannel returns a mention of the channel with id, e.g. C0123ABC, shown as a link to it
+func MentionChannel(id string) string {
+	return "<#" + mentionId(id) + ">"
+}
+
+// Here returns @here, which notifies active members of the channel
+func Here() string {
+	return "<!here>"
+}
+
+// Channel returns @channel, which notifies all members of the channel
+func Channel() string {
+	return "<!channel>"
+}
+
+// Everyone returns @everyone, which notifies all members of the workspace in #general
+func Everyone() string {
+	return "<!everyone>"
+}
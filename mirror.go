@@ -0,0 +1,121 @@
+package slacker
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"text/template"
+	"time"
+)
+
+// Mirror is an HTTP endpoint every delivered message is also sent to
+type Mirror struct {
+	Url          string
+	Method       string            // Optional, POST by default
+	Header       map[string]string // Optional
+	BodyTemplate string            // Optional, text/template over MirrorData with "json" function like TemplateSink, MirrorData as JSON by default
+	Tags         []string          // Optional, MessageTags mirrored, all if empty
+}
+
+// MirrorData is the data mirrored message body is built from
+type MirrorData struct {
+	MessageTag string    `json:"message_tag"`
+	Text       string    `json:"text"`
+	From       string    `json:"from"`
+	Time       time.Time `json:"time"`
+}
+
+func (mirror Mirror) isMirrored(tag string) bool {
+	if len(mirror.Tags) == 0 {
+		return true
+	}
+
+	for _, mirrored := range mirror.Tags {
+		if mirrored == tag {
+			return true
+		}
+	}
+
+	return false
+}
+
+// name tells the mirror apart in logs and latency stats by its host only,
+// its url may carry a token
+func (mirror Mirror) name() string {
+	parsed, err := url.Parse(mirror.Url)
+	if err != nil || parsed.Host == "" {
+		return "Mirror"
+	}
+
+	return "Mirror " + parsed.Host
+}
+
+func (mirror Mirror) body(data MirrorData) ([]byte, error) {
+	if mirror.BodyTemplate == "" {
+		return json.Marshal(data)
+	}
+
+	tmpl, err := compileTemplate("mirror", "sink", template.FuncMap{"json": toJson}, mirror.BodyTemplate)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to parse body template: %s", err)
+	}
+
+	var body bytes.Buffer
+	if err := tmpl.Execute(&body, data); err != nil {
+		return nil, fmt.Errorf("Failed to execute body template: %s", err)
+	}
+
+	return body.Bytes(), nil
+}
+
+// sendToMirrors sends message to Mirrors of its tag
+func (slacker *Slacker) sendToMirrors(message string) {
+	data := MirrorData{
+		MessageTag: slacker.MessageTag,
+		Text:       message,
+		From:       slacker.From,
+		Time:       time.Now(),
+	}
+
+	for _, mirror := range slacker.Mirrors {
+		if !mirror.isMirrored(slacker.MessageTag) {
+			continue
+		}
+
+		if err := slacker.sendToMirror(mirror, data); err != nil {
+			slacker.Log.Errorf("Slacker failed to mirror message to %s: %s", mirror.name(), err)
+		}
+	}
+}
+
+func (slacker *Slacker) sendToMirror(mirror Mirror, data MirrorData) error {
+	body, err := mirror.body(data)
+	if err != nil {
+		return err
+	}
+
+	method := mirror.Method
+	if method == "" {
+		method = http.MethodPost
+	}
+
+	request, err := http.NewRequest(method, mirror.Url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("Failed to create request to %s", mirror.name())
+	}
+
+	if mirror.BodyTemplate == "" {
+		request.Header.Set("Content-Type", "application/json")
+	}
+	for name, value := range mirror.Header {
+		request.Header.Set(name, value)
+	}
+
+	_, err = slacker.doRequest(mirror.name(), request)
+	if urlErr, ok := err.(*url.Error); ok {
+		urlErr.URL = mirror.name()
+	}
+	return err
+}
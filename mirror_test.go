@@ -0,0 +1,92 @@
+package slacker
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestMirrorBodyTemplateIsCached(t *testing.T) {
+	mirror := Mirror{BodyTemplate: `{"text": "{{.Text}}"}`}
+
+	if _, err := mirror.body(MirrorData{Text: "first"}); err != nil {
+		t.Fatal(err)
+	}
+	before := GetTemplateCacheStats()
+
+	body, err := mirror.body(MirrorData{Text: "second"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(body) != `{"text": "second"}` {
+		t.Fatalf("Unexpected body %s", body)
+	}
+
+	after := GetTemplateCacheStats()
+	if after.Hits != before.Hits+1 || after.Misses != before.Misses {
+		t.Fatalf("Template is not taken from the cache: %+v, then %+v", before, after)
+	}
+}
+
+func TestMirrorBodyTemplateJson(t *testing.T) {
+	mirror := Mirror{BodyTemplate: `{"text": {{json .Text}}}`}
+
+	body, err := mirror.body(MirrorData{Text: `say "hi"`})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(body) != `{"text": "say \"hi\""}` {
+		t.Fatalf("Unexpected body %s", body)
+	}
+}
+
+func TestMirrorUrlIsNotLogged(t *testing.T) {
+	hook := newTestHook(t)
+	logger := &recordingLogger{}
+
+	slacker := newTestSlacker(t, hook)
+	slacker.Log = logger
+	slacker.Mirrors = []Mirror{{Url: "http://127.0.0.1:1/mirror?token=secret0token"}}
+	if err := slacker.Send("mirrored"); err != nil {
+		t.Fatal(err)
+	}
+
+	if logger.contains("secret0token") {
+		t.Fatalf("Mirror url is logged: %v", logger.lines)
+	}
+	if GetLatencyStats()["Mirror 127.0.0.1:1"].Count == 0 {
+		t.Fatal("Mirror latency is not kept by host")
+	}
+}
+
+// recordingLogger keeps formatted log lines
+type recordingLogger struct {
+	mutex sync.Mutex
+	lines []string
+}
+
+func (logger *recordingLogger) log(format string, args ...interface{}) {
+	logger.mutex.Lock()
+	defer logger.mutex.Unlock()
+	logger.lines = append(logger.lines, fmt.Sprintf(format, args...))
+}
+
+func (logger *recordingLogger) Debugf(format string, args ...interface{}) {
+	logger.log(format, args...)
+}
+func (logger *recordingLogger) Infof(format string, args ...interface{}) { logger.log(format, args...) }
+func (logger *recordingLogger) Errorf(format string, args ...interface{}) {
+	logger.log(format, args...)
+}
+
+func (logger *recordingLogger) contains(text string) bool {
+	logger.mutex.Lock()
+	defer logger.mutex.Unlock()
+	for _, line := range logger.lines {
+		if strings.Contains(line, text) {
+			return true
+		}
+	}
+	return false
+}
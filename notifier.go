@@ -0,0 +1,94 @@
+package slacker
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// Notifier delivers messages to a service other than the Slack webhook
+type Notifier interface {
+	Notify(message Message) error
+}
+
+// PayloadRenderer is implemented by sinks which post a request body built from message alone.
+// Payload is pure and its bytes are stable across versions, e.g. for snapshot tests.
+type PayloadRenderer interface {
+	Payload(message Message) ([]byte, error)
+}
+
+// ContextNotifier is a Notifier which also sends messages with ctx. As a sink of Slacker
+// its requests respect the deadline of SendContext and go through the http client of Slacker,
+// with its AllowedHosts, Proxy, TLSConfig, DialContext and Recorder.
+type ContextNotifier interface {
+	Notifier
+	NotifyContext(ctx context.Context, message Message) error
+}
+
+// sinkSlackerKey is the context key of the Slacker sending a message to its sinks
+type sinkSlackerKey struct{}
+
+// defaultSinkClient is used by sinks without their own http client outside of Slacker
+var defaultSinkClient = &http.Client{Timeout: 10 * time.Second}
+
+// notifySinks sends delivered message to every Sinks notifier which accepts it
+func (slacker Slacker) notifySinks(message string) {
+	if slacker.httpClient == nil {
+		slacker.setHttpClient()
+	}
+	ctx := context.WithValue(slacker.context(), sinkSlackerKey{}, &slacker)
+
+	for _, sink := range slacker.Sinks {
+		if err := lintSink(sink, Message{MessageTag: slacker.MessageTag, Text: message}); err != nil {
+			slacker.Log.Errorf("Slacker failed to notify %T: %s", sink, err)
+			continue
+		}
+
+		started := time.Now()
+		err := notify(ctx, sink, Message{MessageTag: slacker.MessageTag, Text: message})
+		slacker.observeLatency(fmt.Sprintf("%T", sink), time.Since(started))
+		if err != nil {
+			slacker.Log.Errorf("Slacker failed to notify %T: %s", sink, err)
+		}
+	}
+}
+
+// notify sends message with notifier, with ctx if it is a ContextNotifier
+func notify(ctx context.Context, notifier Notifier, message Message) error {
+	if contextNotifier, ok := notifier.(ContextNotifier); ok {
+		return contextNotifier.NotifyContext(ctx, message)
+	}
+
+	return notifier.Notify(message)
+}
+
+// doSinkRequest sends request of a sink with ctx and returns the response body if the status is 2xx.
+// Within Slacker it follows its egress rules and uses its http client unless client is set.
+func doSinkRequest(ctx context.Context, client *http.Client, service string, request *http.Request) ([]byte, error) {
+	sender := Slacker{httpClient: defaultSinkClient, Log: defaultLogger()}
+	if slacker, ok := ctx.Value(sinkSlackerKey{}).(*Slacker); ok {
+		sender = *slacker
+	}
+	sender.ctx = ctx
+
+	if client != nil {
+		return sender.doRequestWith(client, service, request)
+	}
+
+	return sender.doRequest(service, request)
+}
+
+// Notify implements Notifier, so a Slacker can be a sink of another Slacker
+func (slacker Slacker) Notify(message Message) error {
+	return slacker.NotifyContext(context.Background(), message)
+}
+
+// NotifyContext implements ContextNotifier with SendContext
+func (slacker Slacker) NotifyContext(ctx context.Context, message Message) error {
+	if message.MessageTag != "" {
+		slacker.MessageTag = message.MessageTag
+	}
+
+	return slacker.SendContext(ctx, message.Text)
+}
@@ -0,0 +1,43 @@
+package slacker
+
+import (
+	"context"
+	"net/url"
+	"testing"
+)
+
+func TestSinksFollowAllowedHosts(t *testing.T) {
+	hook := newTestHook(t)
+	sinkHook := newTestHook(t)
+	slacker := newTestSlacker(t, hook)
+
+	// Both servers listen on 127.0.0.1, the sink is reached through another host name
+	sinkUrl, _ := url.Parse(sinkHook.URL)
+	slacker.AllowedHosts = []string{"127.0.0.1"}
+	slacker.Sinks = []Notifier{TemplateSink{Url: "http://localhost:" + sinkUrl.Port(), Template: `{"text": {{json .Text}}}`}}
+
+	if err := slacker.Send("message"); err != nil {
+		t.Fatal(err)
+	}
+	if len(hook.posted("message")) != 1 {
+		t.Fatalf("Message is not sent to Slack: %v", hook.payloads)
+	}
+	if len(sinkHook.posted("message")) != 0 {
+		t.Fatalf("Sink posted to a host outside of AllowedHosts: %v", sinkHook.payloads)
+	}
+}
+
+func TestChainStopsOnceContextIsDone(t *testing.T) {
+	hook := newTestHook(t)
+	chain := NewChain(TemplateSink{Url: hook.URL, Template: `{"text": {{json .Text}}}`})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if err := chain.NotifyContext(ctx, Message{Text: "message"}); err == nil {
+		t.Fatal("Chain delivered the message with a canceled context")
+	}
+	if len(hook.posted("message")) != 0 {
+		t.Fatalf("Chain posted with a canceled context: %v", hook.payloads)
+	}
+}
@@ -0,0 +1,115 @@
+package slacker
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+const DefaultNtfyServer = "https://ntfy.sh"
+
+// NtfySink publishes messages to an ntfy topic
+type NtfySink struct {
+	Server     string // Optional, DefaultNtfyServer by default
+	Topic      string
+	Token      string       // Optional, access token
+	Priority   int          // Optional, from 1 (min) to 5 (max)
+	HttpClient *http.Client // Optional
+}
+
+// Payload implements PayloadRenderer, ntfy publishes the request body as is
+func (sink NtfySink) Payload(message Message) ([]byte, error) {
+	return []byte(message.Text), nil
+}
+
+// Notify implements Notifier
+func (sink NtfySink) Notify(message Message) error {
+	return sink.NotifyContext(context.Background(), message)
+}
+
+// NotifyContext implements ContextNotifier
+func (sink NtfySink) NotifyContext(ctx context.Context, message Message) error {
+	server := sink.Server
+	if server == "" {
+		server = DefaultNtfyServer
+	}
+
+	payload, err := sink.Payload(message)
+	if err != nil {
+		return err
+	}
+
+	request, err := http.NewRequest(http.MethodPost, strings.TrimRight(server, "/")+"/"+url.PathEscape(sink.Topic), bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+
+	request.Header.Set("Title", message.MessageTag)
+	if sink.Priority > 0 {
+		request.Header.Set("Priority", strconv.Itoa(sink.Priority))
+	}
+	if sink.Token != "" {
+		request.Header.Set("Authorization", "Bearer "+sink.Token)
+	}
+
+	_, err = doSinkRequest(ctx, sink.HttpClient, "ntfy", request)
+	return err
+}
+
+// Lint implements Linter
+func (sink NtfySink) Lint(message Message) error {
+	l := &lint{service: "ntfy"}
+	if sink.Topic == "" {
+		l.problem("topic is empty")
+	}
+	if sink.Priority < 0 || sink.Priority > 5 {
+		l.problem("priority is %d, it must be from 1 to 5", sink.Priority)
+	}
+	return l.err()
+}
+
+// GotifySink pushes messages to a Gotify server with an application token
+type GotifySink struct {
+	Server     string       // e.g. https://gotify.example.org
+	Token      string       // Application token
+	Priority   int          // Optional
+	HttpClient *http.Client // Optional
+}
+
+type gotifyMessage struct {
+	Title    string `json:"title"`
+	Message  string `json:"message"`
+	Priority int    `json:"priority"`
+}
+
+// Payload implements PayloadRenderer
+func (sink GotifySink) Payload(message Message) ([]byte, error) {
+	return json.Marshal(gotifyMessage{Title: message.MessageTag, Message: message.Text, Priority: sink.Priority})
+}
+
+// Notify implements Notifier
+func (sink GotifySink) Notify(message Message) error {
+	return sink.NotifyContext(context.Background(), message)
+}
+
+// NotifyContext implements ContextNotifier
+func (sink GotifySink) NotifyContext(ctx context.Context, message Message) error {
+	payload, err := sink.Payload(message)
+	if err != nil {
+		return err
+	}
+
+	request, err := http.NewRequest(http.MethodPost, strings.TrimRight(sink.Server, "/")+"/message", bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	request.Header.Set("X-Gotify-Key", sink.Token)
+	request.Header.Set("Content-Type", "application/json")
+
+	_, err = doSinkRequest(ctx, sink.HttpClient, "Gotify", request)
+	return err
+}
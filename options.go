@@ -0,0 +1,80 @@
+package slacker
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// Option sets a setting of Slacker created by New
+type Option func(slacker *Slacker)
+
+// New returns Slacker posting to web hook url hook with opts applied
+// and validated, so misconfiguration is found before the first Send
+func New(hook string, opts ...Option) (Slacker, error) {
+	slacker := Slacker{Hook: hook}
+	for _, opt := range opts {
+		opt(&slacker)
+	}
+
+	if err := slacker.Validate(); err != nil {
+		return Slacker{}, fmt.Errorf("Slacker failed to validate settings: %s", err)
+	}
+
+	return slacker, nil
+}
+
+// WithRecipients sets To
+func WithRecipients(recipients ...Recipient) Option {
+	return func(slacker *Slacker) {
+		slacker.To = recipients
+	}
+}
+
+// WithFrequency sets Frequency, NotifyAlways, NotifyOnceHour or NotifyOnceDay
+func WithFrequency(frequency int) Option {
+	return func(slacker *Slacker) {
+		slacker.Frequency = frequency
+	}
+}
+
+// WithMessageTag sets MessageTag
+func WithMessageTag(tag string) Option {
+	return func(slacker *Slacker) {
+		slacker.MessageTag = tag
+	}
+}
+
+// WithDatabaseFilePath sets DatabaseFilePath
+func WithDatabaseFilePath(path string) Option {
+	return func(slacker *Slacker) {
+		slacker.DatabaseFilePath = path
+	}
+}
+
+// WithLogger sets Log
+func WithLogger(logger Logger) Option {
+	return func(slacker *Slacker) {
+		slacker.Log = logger
+	}
+}
+
+// WithHttpClient sets HttpClient
+func WithHttpClient(client *http.Client) Option {
+	return func(slacker *Slacker) {
+		slacker.HttpClient = client
+	}
+}
+
+// WithBotToken sets BotToken, hook of New may be empty then
+func WithBotToken(token string) Option {
+	return func(slacker *Slacker) {
+		slacker.BotToken = token
+	}
+}
+
+// WithStore sets Store, e.g. NewMemoryStore()
+func WithStore(store Store) Option {
+	return func(slacker *Slacker) {
+		slacker.Store = store
+	}
+}
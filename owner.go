@@ -0,0 +1,52 @@
+package slacker
+
+import (
+	"fmt"
+	"path"
+)
+
+// Owner is a team owning message tags, which is mentioned in their messages
+type Owner struct {
+	Name    string   // e.g. "Payments"
+	Mention string   // e.g. MentionGroup("S0123ABC") or MentionUser("U0123ABC")
+	Tags    []string // MessageTags owned, or patterns like "payments-*" matched with path.Match
+}
+
+// owns reports whether owner owns tag
+func (owner Owner) owns(tag string) bool {
+	for _, pattern := range owner.Tags {
+		if matched, err := path.Match(pattern, tag); pattern == tag || (err == nil && matched) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Owner returns the first of Owners owning MessageTag
+func (slacker Slacker) Owner() (Owner, bool) {
+	for _, owner := range slacker.Owners {
+		if owner.owns(slacker.MessageTag) {
+			return owner, true
+		}
+	}
+
+	return Owner{}, false
+}
+
+// ownerMention returns the mention of the owner of MessageTag, or a flag of an unowned tag with FlagUnowned
+func (slacker Slacker) ownerMention() string {
+	if len(slacker.Owners) == 0 {
+		return ""
+	}
+
+	if owner, ok := slacker.Owner(); ok {
+		return owner.Mention
+	}
+
+	if slacker.FlagUnowned {
+		return fmt.Sprintf("[unowned tag %s]", Escape(slacker.MessageTag))
+	}
+
+	return ""
+}
@@ -0,0 +1,174 @@
+package slacker
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// Exec plugins are executables run once per call. A plugin reads a JSON request
+// from stdin and writes a JSON response to stdout, a non-zero exit status fails the call
+// with stderr as the error. Requests and responses are:
+//
+//	ExecSink:     Message                            -> nothing
+//	ExecEnricher: Message                            -> Message
+//	ExecStore:    {"op": "get", "key": "..."}        -> {"found": true, "record": StoreRecord}
+//	              {"op": "put", "records": [...]}    -> {"conflicts": ["key", ...]}
+
+// DefaultPluginTimeout bounds a plugin run without its own Timeout
+const DefaultPluginTimeout time.Duration = 10 * time.Second
+
+// ExecPlugin is an executable run with Args for every call
+type ExecPlugin struct {
+	Command string
+	Args    []string
+	Timeout time.Duration // Optional, DefaultPluginTimeout by default
+}
+
+// run runs the plugin with request as JSON stdin and decodes its JSON stdout into response if it is not nil.
+// The plugin is killed once ctx is done or Timeout is over.
+func (plugin ExecPlugin) run(ctx context.Context, request interface{}, response interface{}) error {
+	input, err := json.Marshal(request)
+	if err != nil {
+		return fmt.Errorf("Failed to encode plugin request: %s", err)
+	}
+
+	timeout := plugin.Timeout
+	if timeout <= 0 {
+		timeout = DefaultPluginTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	var stdout, stderr bytes.Buffer
+	command := exec.CommandContext(ctx, plugin.Command, plugin.Args...)
+	command.Stdin = bytes.NewReader(input)
+	command.Stdout = &stdout
+	command.Stderr = &stderr
+
+	if err := command.Run(); err != nil {
+		return fmt.Errorf("Plugin %s failed: %s: %s", plugin.Command, err, truncate(strings.TrimSpace(stderr.String()), maxDiagnosticsLength))
+	}
+
+	if response == nil {
+		return nil
+	}
+
+	if err := json.Unmarshal(stdout.Bytes(), response); err != nil {
+		return fmt.Errorf("Failed to decode response of plugin %s: %s", plugin.Command, err)
+	}
+
+	return nil
+}
+
+// ExecSink is a Notifier running an exec plugin for every message
+type ExecSink struct {
+	ExecPlugin
+}
+
+// Notify implements Notifier
+func (sink ExecSink) Notify(message Message) error {
+	return sink.NotifyContext(context.Background(), message)
+}
+
+// NotifyContext implements ContextNotifier
+func (sink ExecSink) NotifyContext(ctx context.Context, message Message) error {
+	return sink.run(ctx, message, nil)
+}
+
+// Enricher changes messages before they are deduplicated and sent, e.g. adds links or context
+type Enricher interface {
+	Enrich(message Message) (Message, error)
+}
+
+// ContextEnricher is an Enricher which respects the deadline of SendContext
+type ContextEnricher interface {
+	Enricher
+	EnrichContext(ctx context.Context, message Message) (Message, error)
+}
+
+// ExecEnricher is an Enricher running an exec plugin for every message
+type ExecEnricher struct {
+	ExecPlugin
+}
+
+// Enrich implements Enricher
+func (enricher ExecEnricher) Enrich(message Message) (Message, error) {
+	return enricher.EnrichContext(context.Background(), message)
+}
+
+// EnrichContext implements ContextEnricher
+func (enricher ExecEnricher) EnrichContext(ctx context.Context, message Message) (enriched Message, err error) {
+	err = enricher.run(ctx, message, &enriched)
+	return enriched, err
+}
+
+// enrich applies Enrichers to message, a failed enricher is skipped
+func (slacker *Slacker) enrich(message string) string {
+	for _, enricher := range slacker.Enrichers {
+		var enriched Message
+		var err error
+		if contextEnricher, ok := enricher.(ContextEnricher); ok {
+			enriched, err = contextEnricher.EnrichContext(slacker.context(), Message{MessageTag: slacker.MessageTag, Text: message})
+		} else {
+			enriched, err = enricher.Enrich(Message{MessageTag: slacker.MessageTag, Text: message})
+		}
+		if err != nil {
+			slacker.Log.Errorf("Slacker failed to enrich message %s with %T: %s", slacker.MessageTag, enricher, err)
+			continue
+		}
+
+		if enriched.MessageTag != "" {
+			slacker.MessageTag = enriched.MessageTag
+		}
+		message = enriched.Text
+	}
+
+	return message
+}
+
+// ExecStore is a Store running an exec plugin for every operation
+type ExecStore struct {
+	ExecPlugin
+}
+
+type execStoreRequest struct {
+	Op      string        `json:"op"`
+	Key     string        `json:"key,omitempty"`
+	Records []StoreRecord `json:"records,omitempty"`
+}
+
+type execStoreResponse struct {
+	Found     bool        `json:"found"`
+	Record    StoreRecord `json:"record"`
+	Conflicts []string    `json:"conflicts"`
+}
+
+// Get implements Store
+func (store ExecStore) Get(key string) (record StoreRecord, found bool, err error) {
+	var response execStoreResponse
+	if err := store.run(context.Background(), execStoreRequest{Op: "get", Key: key}, &response); err != nil {
+		return record, false, err
+	}
+
+	return response.Record, response.Found, nil
+}
+
+// Put implements Store
+func (store ExecStore) Put(records []StoreRecord) (conflicts map[string]bool, err error) {
+	var response execStoreResponse
+	if err := store.run(context.Background(), execStoreRequest{Op: "put", Records: records}, &response); err != nil {
+		return nil, err
+	}
+
+	conflicts = make(map[string]bool, len(response.Conflicts))
+	for _, key := range response.Conflicts {
+		conflicts[key] = true
+	}
+
+	return conflicts, nil
+}
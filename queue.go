@@ -0,0 +1,96 @@
+package slacker
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+)
+
+var (
+	// ErrQueueFull is returned by SendAsync when the queue buffer has no room for the message
+	ErrQueueFull = errors.New("Send queue is full")
+	// ErrQueueClosed is returned by SendAsync after Close
+	ErrQueueClosed = errors.New("Send queue is closed")
+)
+
+// Queue sends messages with Slacker settings in background goroutines
+type Queue struct {
+	slacker  Slacker
+	messages chan string
+	pending  sync.WaitGroup // Messages queued but not yet sent
+	workers  sync.WaitGroup
+	lock     sync.RWMutex
+	closed   bool
+}
+
+// StartQueue starts workers goroutines sending messages queued with SendAsync.
+// Up to size messages wait in the queue, SendAsync returns ErrQueueFull beyond it.
+// Call Close on shutdown to send the queued messages and stop the workers.
+func (slacker Slacker) StartQueue(workers int, size int) (*Queue, error) {
+	if err := slacker.setDefaults(); err != nil {
+		return nil, fmt.Errorf("Slacker failed to start queue: %s", err)
+	}
+
+	if workers < 1 {
+		workers = 1
+	}
+
+	queue := &Queue{
+		slacker:  slacker,
+		messages: make(chan string, size),
+	}
+
+	queue.workers.Add(workers)
+	for i := 0; i < workers; i++ {
+		go queue.work()
+	}
+
+	return queue, nil
+}
+
+func (queue *Queue) work() {
+	defer queue.workers.Done()
+
+	for message := range queue.messages {
+		// Send logs and emits its errors itself
+		queue.slacker.Send(message)
+		queue.pending.Done()
+	}
+}
+
+// SendAsync queues message to be sent like Send without waiting for it
+func (queue *Queue) SendAsync(message string) error {
+	queue.lock.RLock()
+	defer queue.lock.RUnlock()
+
+	if queue.closed {
+		return ErrQueueClosed
+	}
+
+	queue.pending.Add(1)
+	select {
+	case queue.messages <- message:
+		return nil
+	default:
+		queue.pending.Done()
+		queue.slacker.Log.Errorf("Skip message %s, send queue is full: %s", queue.slacker.MessageTag, message)
+		return ErrQueueFull
+	}
+}
+
+// Flush waits until messages queued so far are sent
+func (queue *Queue) Flush() {
+	queue.pending.Wait()
+}
+
+// Close stops accepting messages, sends the queued ones and stops the workers
+func (queue *Queue) Close() {
+	queue.lock.Lock()
+	if !queue.closed {
+		queue.closed = true
+		close(queue.messages)
+	}
+	queue.lock.Unlock()
+
+	queue.workers.Wait()
+}
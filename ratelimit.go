@@ -0,0 +1,72 @@
+package slacker
+
+import (
+	"sync"
+	"time"
+)
+
+// tokenBucket limits the rate of posts to a Slack url
+type tokenBucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// buckets holds token buckets by Slack url, so copies of a Slacker share the limit
+var buckets = struct {
+	sync.Mutex
+	byUrl map[string]*tokenBucket
+}{byUrl: make(map[string]*tokenBucket)}
+
+// waitRateLimit reserves a post within RateLimit and waits until it may be done
+func (slacker *Slacker) waitRateLimit() error {
+	if slacker.RateLimit <= 0 {
+		return nil
+	}
+
+	wait := slacker.reservePost(time.Now())
+	if wait <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+
+	select {
+	case <-slacker.context().Done():
+		return slacker.context().Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// reservePost takes a token from the bucket of the Slack url at now
+// and returns how long to wait until the token is available
+func (slacker *Slacker) reservePost(now time.Time) time.Duration {
+	burst := float64(slacker.RateBurst)
+	if burst < 1 {
+		burst = 1
+	}
+
+	buckets.Lock()
+	defer buckets.Unlock()
+
+	url := slacker.postUrl()
+	bucket, ok := buckets.byUrl[url]
+	if !ok {
+		bucket = &tokenBucket{tokens: burst, last: now}
+		buckets.byUrl[url] = bucket
+	}
+
+	bucket.tokens += now.Sub(bucket.last).Seconds() * slacker.RateLimit
+	if bucket.tokens > burst {
+		bucket.tokens = burst
+	}
+	bucket.last = now
+	bucket.tokens--
+
+	if bucket.tokens >= 0 {
+		return 0
+	}
+
+	return time.Duration(-bucket.tokens / slacker.RateLimit * float64(time.Second))
+}
@@ -0,0 +1,296 @@
+package slacker
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// redacted replaces tokens and ids in fixtures
+const redacted = "REDACTED"
+
+// Recorder modes
+const (
+	RecorderRecord int = 1 // Pass requests through and save interactions to the fixture file
+	RecorderReplay int = 2 // Answer requests from the fixture file without network access
+)
+
+// Interaction is a recorded request and its response
+type Interaction struct {
+	Method       string      `json:"method"`
+	Url          string      `json:"url"`
+	RequestBody  string      `json:"request_body"`
+	StatusCode   int         `json:"status_code"`
+	Header       http.Header `json:"header"`
+	ResponseBody string      `json:"response_body"`
+}
+
+// Recorder records HTTP interactions of Slacker to a fixture file and replays them,
+// so tests of services using Slacker do not need a live webhook.
+// Fixtures keep no secrets: query values, path segments mixing letters and digits,
+// e.g. of webhook urls, and token, secret, password and key fields of bodies are redacted.
+// Requests are replayed by their method, redacted url and redacted body.
+type Recorder struct {
+	Path string
+	Mode int
+
+	mutex        sync.Mutex
+	loaded       bool
+	interactions []Interaction
+	replayed     []bool
+	next         http.RoundTripper
+}
+
+// NewRecorder returns Recorder for fixture file path in mode RecorderRecord or RecorderReplay
+func NewRecorder(path string, mode int) *Recorder {
+	return &Recorder{Path: path, Mode: mode}
+}
+
+// RoundTrip implements http.RoundTripper
+func (recorder *Recorder) RoundTrip(request *http.Request) (*http.Response, error) {
+	var requestBody []byte
+	if request.Body != nil {
+		var err error
+		requestBody, err = ioutil.ReadAll(request.Body)
+		if err != nil {
+			return nil, err
+		}
+		request.Body.Close()
+		request.Body = ioutil.NopCloser(bytes.NewReader(requestBody))
+	}
+
+	if recorder.Mode == RecorderReplay {
+		return recorder.replay(request, redactBody(string(requestBody)))
+	}
+
+	return recorder.record(request, redactBody(string(requestBody)))
+}
+
+func (recorder *Recorder) record(request *http.Request, requestBody string) (*http.Response, error) {
+	recorder.mutex.Lock()
+	next := recorder.next
+	recorder.mutex.Unlock()
+
+	if next == nil {
+		next = http.DefaultTransport
+	}
+
+	response, err := next.RoundTrip(request)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	responseBody, err := ioutil.ReadAll(response.Body)
+	if err != nil {
+		return nil, err
+	}
+	response.Body = ioutil.NopCloser(bytes.NewReader(responseBody))
+
+	header := response.Header.Clone()
+	header.Del("Set-Cookie")
+
+	recorder.mutex.Lock()
+	defer recorder.mutex.Unlock()
+
+	recorder.interactions = append(recorder.interactions, Interaction{
+		Method:       request.Method,
+		Url:          redactUrl(request.URL),
+		RequestBody:  requestBody,
+		StatusCode:   response.StatusCode,
+		Header:       header,
+		ResponseBody: string(responseBody),
+	})
+
+	if err := recorder.save(); err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+func (recorder *Recorder) replay(request *http.Request, requestBody string) (*http.Response, error) {
+	recorder.mutex.Lock()
+	defer recorder.mutex.Unlock()
+
+	if !recorder.loaded {
+		if err := recorder.load(); err != nil {
+			return nil, err
+		}
+	}
+
+	requestUrl := redactUrl(request.URL)
+	for i, interaction := range recorder.interactions {
+		if recorder.replayed[i] || interaction.Method != request.Method ||
+			interaction.Url != requestUrl || interaction.RequestBody != requestBody {
+			continue
+		}
+
+		recorder.replayed[i] = true
+		return &http.Response{
+			Status:        fmt.Sprintf("%d %s", interaction.StatusCode, http.StatusText(interaction.StatusCode)),
+			StatusCode:    interaction.StatusCode,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        interaction.Header,
+			Body:          ioutil.NopCloser(bytes.NewBufferString(interaction.ResponseBody)),
+			ContentLength: int64(len(interaction.ResponseBody)),
+			Request:       request,
+		}, nil
+	}
+
+	return nil, fmt.Errorf("Recorder has no interaction for %s %s with body %s", request.Method, requestUrl, requestBody)
+}
+
+func (recorder *Recorder) load() error {
+	data, err := ioutil.ReadFile(recorder.Path)
+	if err != nil {
+		return fmt.Errorf("Recorder failed to load %s: %s", recorder.Path, err)
+	}
+
+	if err := json.Unmarshal(data, &recorder.interactions); err != nil {
+		return fmt.Errorf("Recorder failed to load %s: %s", recorder.Path, err)
+	}
+
+	recorder.replayed = make([]bool, len(recorder.interactions))
+	recorder.loaded = true
+
+	return nil
+}
+
+func (recorder *Recorder) save() error {
+	data, err := json.MarshalIndent(recorder.interactions, "", "  ")
+	if err != nil {
+		return fmt.Errorf("Recorder failed to save %s: %s", recorder.Path, err)
+	}
+
+	if err := ioutil.WriteFile(recorder.Path, data, os.FileMode(0644)); err != nil {
+		return fmt.Errorf("Recorder failed to save %s: %s", recorder.Path, err)
+	}
+
+	return nil
+}
+
+// setNext sets the transport used to pass recorded requests through
+func (recorder *Recorder) setNext(next http.RoundTripper) {
+	recorder.mutex.Lock()
+	defer recorder.mutex.Unlock()
+
+	recorder.next = next
+}
+
+// redactUrl returns u without user info, with query values and path segments
+// which may be ids or tokens replaced by redacted
+func redactUrl(u *url.URL) string {
+	redactedUrl := *u
+	redactedUrl.User = nil
+	redactedUrl.RawPath = ""
+
+	segments := strings.Split(u.Path, "/")
+	for i, segment := range segments {
+		if isIdLike(segment) {
+			segments[i] = redacted
+		}
+	}
+	redactedUrl.Path = strings.Join(segments, "/")
+
+	query := u.Query()
+	for key := range query {
+		query[key] = []string{redacted}
+	}
+	redactedUrl.RawQuery = query.Encode()
+
+	return redactedUrl.String()
+}
+
+// isIdLike reports whether path segment is long and mixes letters and digits
+// like webhook ids, bot tokens and account ids do
+func isIdLike(segment string) bool {
+	if len(segment) < 8 {
+		return false
+	}
+
+	letters, digits := false, false
+	for _, r := range segment {
+		letters = letters || unicode.IsLetter(r)
+		digits = digits || unicode.IsDigit(r)
+	}
+
+	return letters && digits
+}
+
+// isSecretField reports whether a body field of name holds a credential
+func isSecretField(name string) bool {
+	name = strings.ToLower(name)
+	for _, secret := range []string{"token", "secret", "password", "key"} {
+		if strings.Contains(name, secret) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// redactBody returns JSON or form body with secret fields replaced by redacted,
+// other bodies are returned as is
+func redactBody(body string) string {
+	var value interface{}
+	if err := json.Unmarshal([]byte(body), &value); err == nil {
+		if !redactJson(value) {
+			return body
+		}
+
+		data, err := json.Marshal(value)
+		if err != nil {
+			return body
+		}
+		return string(data)
+	}
+
+	form, err := url.ParseQuery(body)
+	if err != nil {
+		return body
+	}
+
+	found := false
+	for key := range form {
+		if isSecretField(key) {
+			form[key] = []string{redacted}
+			found = true
+		}
+	}
+	if !found {
+		return body
+	}
+
+	return form.Encode()
+}
+
+// redactJson replaces secret fields of objects within value and reports whether it found any
+func redactJson(value interface{}) (found bool) {
+	switch value := value.(type) {
+	case map[string]interface{}:
+		for key, field := range value {
+			if isSecretField(key) {
+				value[key] = redacted
+				found = true
+				continue
+			}
+			found = redactJson(field) || found
+		}
+	case []interface{}:
+		for _, item := range value {
+			found = redactJson(item) || found
+		}
+	}
+
+	return found
+}
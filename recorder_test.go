@@ -0,0 +1,93 @@
+package slacker
+
+import (
+	"io/ioutil"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestRecorderRedactsFixtures(t *testing.T) {
+	hook := newTestHook(t)
+	fixture := filepath.Join(t.TempDir(), "fixture.json")
+
+	slacker := newTestSlacker(t, hook)
+	slacker.Hook = hook.URL + "/services/T0001ABCD/B0001ABCD/secret0token1?token=query0secret"
+	slacker.Frequency = NotifyAlways
+	slacker.Recorder = NewRecorder(fixture, RecorderRecord)
+	if err := slacker.Send("recorded"); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := ioutil.ReadFile(fixture)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, secret := range []string{"T0001ABCD", "B0001ABCD", "secret0token1", "query0secret"} {
+		if strings.Contains(string(data), secret) {
+			t.Fatalf("Fixture keeps %s: %s", secret, data)
+		}
+	}
+
+	hook.Close()
+	slacker = newTestSlacker(t, hook)
+	slacker.Hook = hook.URL + "/services/T0002ABCD/B0002ABCD/other0token2?token=other0secret"
+	slacker.Frequency = NotifyAlways
+	slacker.Recorder = NewRecorder(fixture, RecorderReplay)
+	if err := slacker.Send("recorded"); err != nil {
+		t.Fatalf("Request with other tokens is not replayed: %s", err)
+	}
+}
+
+func TestRedactBody(t *testing.T) {
+	for body, expected := range map[string]string{
+		`{"text":"hi","token":"abc"}`:  `{"text":"hi","token":"REDACTED"}`,
+		`{"text":"hi"}`:                `{"text":"hi"}`,
+		"To=%2B1&Body=hi&api_key=abc":  "Body=hi&To=%2B1&api_key=REDACTED",
+		"plain text with token=inside": "plain+text+with+token=REDACTED",
+		"plain text":                   "plain text",
+	} {
+		if redactBody(body) != expected {
+			t.Errorf("Body %s is redacted to %s, expected %s", body, redactBody(body), expected)
+		}
+	}
+}
+
+// barrierTransport answers requests once parties of them are in flight at the same time
+type barrierTransport struct {
+	wait sync.WaitGroup
+}
+
+func (transport *barrierTransport) RoundTrip(request *http.Request) (*http.Response, error) {
+	transport.wait.Done()
+	transport.wait.Wait()
+	return &http.Response{StatusCode: http.StatusOK, Body: ioutil.NopCloser(strings.NewReader("ok")), Request: request}, nil
+}
+
+func TestRecorderPassesConcurrentRequests(t *testing.T) {
+	transport := &barrierTransport{}
+	transport.wait.Add(2)
+
+	recorder := NewRecorder(filepath.Join(t.TempDir(), "fixture.json"), RecorderRecord)
+	recorder.setNext(transport)
+
+	done := make(chan struct{})
+	for i := 0; i < 2; i++ {
+		go func() {
+			request, _ := http.NewRequest(http.MethodGet, "http://localhost/", nil)
+			recorder.RoundTrip(request)
+			done <- struct{}{}
+		}()
+	}
+
+	for i := 0; i < 2; i++ {
+		select {
+		case <-done:
+		case <-time.After(5 * time.Second):
+			t.Fatal("Recorder passes requests through one at a time")
+		}
+	}
+}
@@ -0,0 +1,123 @@
+// Package redisstore keeps Slacker records in Redis, so Slackers of several hosts
+// sharing a Redis send every message once across the cluster.
+//
+// Slacker is built in GOPATH mode without go.mod, so go-redis and its dependencies have to be
+// checked out at the versions redisstore is tested with, the /v9 import suffix is resolved
+// by minimal module compatibility:
+//
+//	git clone --branch v9.5.1 https://github.com/redis/go-redis $GOPATH/src/github.com/redis/go-redis
+//	git clone --branch v2.3.0 https://github.com/cespare/xxhash $GOPATH/src/github.com/cespare/xxhash
+//	git clone https://github.com/dgryski/go-rendezvous $GOPATH/src/github.com/dgryski/go-rendezvous
+//	go build github.com/oneumyvakin/slacker/redisstore
+package redisstore
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/oneumyvakin/slacker"
+	"github.com/redis/go-redis/v9"
+)
+
+// DefaultPrefix is prepended to record keys. In Redis Cluster keep a hash tag in it,
+// so records written together by one Put are in one slot.
+const DefaultPrefix = "{slacker}:"
+
+// putScript writes every record whose stored version equals the expected one, or any if forced,
+// and expires it with its TTL. A new record is written only if its key does not exist, like SETNX.
+// ARGV holds expected version, force flag, value and TTL in milliseconds, 0 for none, of every key.
+var putScript = redis.NewScript(`
+local conflicts = {}
+for i, key in ipairs(KEYS) do
+	local base = (i - 1) * 4
+	local expected = tonumber(ARGV[base + 1])
+	local current = tonumber(redis.call("HGET", key, "version") or "0")
+	if ARGV[base + 2] == "1" or current == expected then
+		redis.call("HSET", key, "version", current + 1, "value", ARGV[base + 3])
+		local ttl = tonumber(ARGV[base + 4])
+		if ttl > 0 then
+			redis.call("PEXPIRE", key, ttl)
+		else
+			redis.call("PERSIST", key)
+		end
+	else
+		table.insert(conflicts, key)
+	end
+end
+return conflicts
+`)
+
+// Store implements slacker.Store on Redis
+type Store struct {
+	client redis.UniversalClient
+	prefix string
+}
+
+// New returns Store keeping records in client with keys prefixed by prefix, DefaultPrefix if empty
+func New(client redis.UniversalClient, prefix string) *Store {
+	if prefix == "" {
+		prefix = DefaultPrefix
+	}
+
+	return &Store{client: client, prefix: prefix}
+}
+
+// Get implements slacker.Store
+func (store *Store) Get(key string) (record slacker.StoreRecord, found bool, err error) {
+	values, err := store.client.HMGet(context.Background(), store.prefix+key, "version", "value").Result()
+	if err != nil {
+		return record, false, fmt.Errorf("Failed to get record %s from Redis: %s", key, err)
+	}
+
+	version, ok := values[0].(string)
+	if !ok {
+		return record, false, nil
+	}
+	value, _ := values[1].(string)
+
+	record.Key = key
+	record.Value = []byte(value)
+	record.Version, err = strconv.Atoi(version)
+	if err != nil {
+		return record, false, fmt.Errorf("Failed to decode version of record %s from Redis: %s", key, err)
+	}
+
+	return record, true, nil
+}
+
+// Put implements slacker.Store, all records are written by one atomic script
+func (store *Store) Put(records []slacker.StoreRecord) (conflicts map[string]bool, err error) {
+	keys := make([]string, 0, len(records))
+	args := make([]interface{}, 0, 4*len(records))
+	for _, record := range records {
+		force := "0"
+		if record.Force {
+			force = "1"
+		}
+
+		var ttl int64
+		if !record.Expires.IsZero() {
+			ttl = time.Until(record.Expires).Milliseconds()
+			if ttl <= 0 {
+				ttl = 1
+			}
+		}
+
+		keys = append(keys, store.prefix+record.Key)
+		args = append(args, record.Version, force, record.Value, ttl)
+	}
+
+	conflicted, err := putScript.Run(context.Background(), store.client, keys, args...).StringSlice()
+	if err != nil {
+		return nil, fmt.Errorf("Failed to put records to Redis: %s", err)
+	}
+
+	conflicts = make(map[string]bool, len(conflicted))
+	for _, key := range conflicted {
+		conflicts[key[len(store.prefix):]] = true
+	}
+
+	return conflicts, nil
+}
@@ -0,0 +1,64 @@
+package slacker
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+)
+
+// Replay posts again messages recorded as sent in the events file written by FileEventSink,
+// e.g. into a newly created incident channel.
+// Only messages with MessageTag tag, any if empty, sent at or after since are replayed.
+// Replayed messages are not deduplicated, counted in Stats or emitted again,
+// messages of DryRun are skipped and the number of replayed messages is returned.
+// Events written with FileEventSink Codec are decoded with Codec of slacker.
+func (slacker Slacker) Replay(eventsPath string, tag string, since time.Time) (replayed int, err error) {
+	if err := slacker.setDefaults(); err != nil {
+		return 0, fmt.Errorf("Slacker failed to replay events: %s", err)
+	}
+
+	file, err := os.Open(eventsPath)
+	if err != nil {
+		return 0, fmt.Errorf("Slacker failed to replay events: %s", err)
+	}
+	defer file.Close()
+
+	var events []Event
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), maxLineLength)
+	for scanner.Scan() {
+		line, err := decodeLine(slacker.Codec, scanner.Bytes())
+		if err != nil {
+			return 0, fmt.Errorf("Slacker failed to replay events: %s", err)
+		}
+
+		var event Event
+		if err := json.Unmarshal(line, &event); err != nil {
+			return 0, fmt.Errorf("Slacker failed to replay events: Failed to decode event: %s", err)
+		}
+
+		if event.Type != EventSend || event.DryRun || event.Time.Before(since) || (tag != "" && event.MessageTag != tag) {
+			continue
+		}
+
+		events = append(events, event)
+	}
+	if err := scanner.Err(); err != nil {
+		return 0, fmt.Errorf("Slacker failed to replay events: %s", err)
+	}
+
+	// Replays do not replay themselves and recorded texts already carry Labels
+	slacker.replaying = true
+	slacker.Labels = nil
+	for _, event := range events {
+		slacker.MessageTag = event.MessageTag
+		if err := slacker.deliver(event.Text, "", nil); err != nil {
+			return replayed, err
+		}
+		replayed++
+	}
+
+	return replayed, nil
+}
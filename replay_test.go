@@ -0,0 +1,70 @@
+package slacker
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestReplayLongEvents(t *testing.T) {
+	hook := newTestHook(t)
+	slacker := newTestSlacker(t, hook)
+
+	// JSON writes every < as the 6 byte \u003c escape
+	text := strings.Repeat("<", DefaultMaxMessageLength)
+	eventsPath := filepath.Join(t.TempDir(), "events.jsonl")
+	sink := &FileEventSink{Path: eventsPath}
+	if err := sink.Emit(Event{Type: EventSend, MessageTag: slacker.MessageTag, Text: text, Time: time.Now()}); err != nil {
+		t.Fatal(err)
+	}
+
+	replayed, err := slacker.Replay(eventsPath, "", time.Time{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if replayed != 1 {
+		t.Fatalf("Replayed %d events", replayed)
+	}
+}
+
+func TestReplaySendsRecordedTextOnce(t *testing.T) {
+	hook := newTestHook(t)
+	eventsPath := filepath.Join(t.TempDir(), "events.jsonl")
+
+	slacker := newTestSlacker(t, hook)
+	slacker.Frequency = NotifyAlways
+	slacker.Labels = []string{"env=prod"}
+	slacker.Statistics = true
+	slacker.Events = &FileEventSink{Path: eventsPath}
+
+	dryRun := slacker
+	dryRun.DryRun = true
+	if err := dryRun.Send("rehearsal"); err != nil {
+		t.Fatal(err)
+	}
+	if err := slacker.Send("incident"); err != nil {
+		t.Fatal(err)
+	}
+
+	replayed, err := slacker.Replay(eventsPath, "", time.Time{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if replayed != 1 || len(hook.posted("rehearsal")) != 0 {
+		t.Fatalf("Replayed %d events, dry run ones are not skipped: %v", replayed, hook.payloads)
+	}
+
+	posted := hook.posted("incident")
+	if len(posted) != 2 || strings.Count(posted[1], "env=prod") != 1 {
+		t.Fatalf("Replayed message is labeled again: %v", posted)
+	}
+
+	buckets, err := slacker.Stats(24*time.Hour, time.Now().Add(-48*time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(buckets) != 1 || buckets[0].Sent != 2 {
+		t.Fatalf("Replayed message is counted again: %v", buckets)
+	}
+}
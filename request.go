@@ -0,0 +1,46 @@
+package slacker
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"time"
+)
+
+// doRequest sends request with Slacker http client and egress rules
+// and returns the response body if the status is 2xx
+func (slacker *Slacker) doRequest(service string, request *http.Request) ([]byte, error) {
+	if slacker.httpClient == nil {
+		slacker.setHttpClient()
+	}
+
+	return slacker.doRequestWith(slacker.httpClient, service, request)
+}
+
+// doRequestWith sends request like doRequest, but with client
+func (slacker *Slacker) doRequestWith(client *http.Client, service string, request *http.Request) ([]byte, error) {
+	if err := slacker.checkEgress(request.URL.String()); err != nil {
+		return nil, err
+	}
+
+	started := time.Now()
+	response, err := client.Do(request.WithContext(slacker.context()))
+	slacker.observeLatency(service, time.Since(started))
+	if response != nil {
+		defer slacker.ioClose(response.Body)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := ioutil.ReadAll(response.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		return nil, fmt.Errorf("Response from %s: %s, body: %s", service, response.Status, truncate(string(body), maxDiagnosticsLength))
+	}
+
+	return body, nil
+}
@@ -0,0 +1,46 @@
+package slacker
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// RecipientResult holds the outcome of a message delivery to a Recipient
+type RecipientResult struct {
+	Recipient Recipient
+	Sent      bool // True if the recipient got the message, also by an earlier interrupted Send
+	Err       error
+}
+
+// MultiError lists recipients SendAll failed to deliver a message to
+type MultiError []RecipientResult
+
+func (multiError MultiError) Error() string {
+	failures := make([]string, 0, len(multiError))
+	for _, result := range multiError {
+		failures = append(failures, fmt.Sprintf("%s: %s", result.Recipient.id(), result.Err))
+	}
+
+	return fmt.Sprintf("Slacker failed to deliver message to %d recipients: %s", len(multiError), strings.Join(failures, "; "))
+}
+
+// SendAll sends message like Send, but a failed recipient does not stop delivery to the rest.
+// It returns the result of every recipient, none if the message was suppressed,
+// and a MultiError of failed recipients. The message is recorded as sent
+// if at least one recipient got it, so failed recipients are not retried.
+func (slacker Slacker) SendAll(message string) (results []RecipientResult, err error) {
+	slacker.results = &results
+	_, err = slacker.sendContext(context.Background(), message)
+	return results, err
+}
+
+// addResult adds the delivery result of recipient for SendAll
+func (slacker Slacker) addResult(recipient Recipient, err error) RecipientResult {
+	result := RecipientResult{Recipient: recipient, Sent: err == nil, Err: err}
+	if slacker.results != nil {
+		*slacker.results = append(*slacker.results, result)
+	}
+
+	return result
+}
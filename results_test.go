@@ -0,0 +1,46 @@
+package slacker
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFailedPostDoesNotBlockTheTag(t *testing.T) {
+	hook := newTestHook(t)
+	slacker := newTestSlacker(t, hook)
+
+	hook.setFail(true)
+	if err := slacker.Send("down"); err == nil {
+		t.Fatal("Failed post returns no error")
+	}
+
+	hook.setFail(false)
+	if err := slacker.Send("down"); err != nil {
+		t.Fatal(err)
+	}
+	if len(hook.posted("down")) != 1 {
+		t.Fatalf("Message is not sent again after a failed post: %v", hook.payloads)
+	}
+}
+
+func TestPartialDeliveryIsNotSentAgain(t *testing.T) {
+	hook := newTestHook(t)
+	slacker := newTestSlacker(t, hook)
+	slacker.OversizeAction = OversizeReject
+	slacker.MaxMessageLength = 100
+	slacker.To = []Recipient{
+		{Channel: "#alerts"},
+		{Channel: "#team", Username: strings.Repeat("u", 200)},
+	}
+
+	results, err := slacker.SendAll("partial")
+	if err == nil || len(results) != 2 {
+		t.Fatalf("Rejected recipient is not reported: %v %v", results, err)
+	}
+	if err := slacker.Send("partial"); err != nil {
+		t.Fatal(err)
+	}
+	if len(hook.posted("partial")) != 1 {
+		t.Fatalf("Message delivered to some recipients is sent again: %v", hook.payloads)
+	}
+}
@@ -0,0 +1,100 @@
+package slacker
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math/rand"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// sendWithRetries posts message and retries transient failures up to MaxRetries times.
+// Rate limited posts are retried after the Retry-After wait of Slack instead of the backoff.
+func (slacker *Slacker) sendWithRetries(message SlackMessage) (response string, err error) {
+	backoff := slacker.InitialBackoff
+	for attempt := 0; ; attempt++ {
+		response, err = slacker.send(message)
+		if err == nil || attempt >= slacker.MaxRetries || !isTransient(err) {
+			return
+		}
+
+		wait := slacker.jitter(backoff)
+		if retryAfter, ok := retryAfter(err); ok {
+			wait = retryAfter
+		}
+		slacker.Log.Errorf("Slacker failed to send message to %s, attempt %d of %d, retry in %s: %s",
+			message.Channel, attempt+1, slacker.MaxRetries+1, wait, err)
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-slacker.context().Done():
+			timer.Stop()
+			return "", slacker.context().Err()
+		case <-timer.C:
+		}
+
+		backoff *= 2
+		if backoff > slacker.MaxBackoff {
+			backoff = slacker.MaxBackoff
+		}
+	}
+}
+
+// isTransient reports whether a failed post may succeed if retried
+func isTransient(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	var responseErr *ResponseError
+	if errors.As(err, &responseErr) {
+		return responseErr.StatusCode >= 500 || responseErr.StatusCode == http.StatusTooManyRequests
+	}
+
+	// Errors of the http client are wrapped in *url.Error, which is a net.Error whatever the cause,
+	// so only timeouts and connection failures are retried, not e.g. certificate or redirect errors
+	var timeout interface{ Timeout() bool }
+	if errors.As(err, &timeout) && timeout.Timeout() {
+		return true
+	}
+
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) {
+		return dnsErr.IsTemporary || dnsErr.IsTimeout
+	}
+
+	var opErr *net.OpError
+	if errors.As(err, &opErr) {
+		return true
+	}
+
+	// The server closed the connection before responding
+	return errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF)
+}
+
+// retryAfter returns the wait Slack asked for in the Retry-After header of a 429 response
+func retryAfter(err error) (time.Duration, bool) {
+	var responseErr *ResponseError
+	if !errors.As(err, &responseErr) || responseErr.StatusCode != http.StatusTooManyRequests {
+		return 0, false
+	}
+
+	seconds, parseErr := strconv.Atoi(responseErr.Header.Get("Retry-After"))
+	if parseErr != nil || seconds < 0 {
+		return 0, false
+	}
+
+	return time.Duration(seconds) * time.Second, true
+}
+
+func (slacker *Slacker) jitter(backoff time.Duration) time.Duration {
+	if slacker.Jitter <= 0 {
+		return backoff
+	}
+
+	factor := 1 + slacker.Jitter*(2*rand.Float64()-1)
+	return time.Duration(float64(backoff) * factor)
+}
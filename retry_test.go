@@ -0,0 +1,35 @@
+package slacker
+
+import (
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestCertificateErrorIsNotRetried(t *testing.T) {
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	server.Config.ErrorLog = log.New(ioutil.Discard, "", 0)
+	server.StartTLS()
+	defer server.Close()
+
+	hook := newTestHook(t)
+	slacker := newTestSlacker(t, hook)
+	slacker.Hook = server.URL
+	slacker.Frequency = NotifyAlways
+	slacker.MaxRetries = 3
+	slacker.InitialBackoff = time.Millisecond
+
+	err := slacker.Send("message")
+	if err == nil {
+		t.Fatal("Send succeeded with an untrusted certificate")
+	}
+	if isTransient(err) {
+		t.Fatalf("Certificate error is transient: %s", err)
+	}
+	if !isTransient(&ResponseError{StatusCode: http.StatusBadGateway}) {
+		t.Fatal("5xx response is not transient")
+	}
+}
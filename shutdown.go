@@ -0,0 +1,85 @@
+package slacker
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+)
+
+const DefaultShutdownTimeout time.Duration = 10 * time.Second
+
+// ShutdownConfig holds settings of NotifyOnShutdown
+type ShutdownConfig struct {
+	Timeout time.Duration // Optional, bounds flushing and sending the message, DefaultShutdownTimeout by default
+	Queues  []*Queue      // Optional, their queued messages are sent before the message
+
+	// Exit is optional, it is called once the message is sent, e.g. to stop a service gracefully.
+	// By default the process exits with code 128 + signal number like it would without the handler.
+	Exit func(sig os.Signal)
+}
+
+// NotifyOnShutdown sends message returned by messageFunc tagged by tag
+// when the process receives SIGINT or SIGTERM. Messages queued in config Queues
+// and the digest of MessageTag are sent before it.
+func (slacker Slacker) NotifyOnShutdown(tag string, messageFunc func(sig os.Signal) string, config ShutdownConfig) {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+
+	go func() {
+		sig := <-signals
+		signal.Stop(signals)
+
+		slacker.shutdown(tag, messageFunc(sig), config)
+
+		if config.Exit != nil {
+			config.Exit(sig)
+			return
+		}
+
+		code := 1
+		if number, ok := sig.(syscall.Signal); ok {
+			code = 128 + int(number)
+		}
+		os.Exit(code)
+	}()
+}
+
+// shutdown sends queued messages, the digest of MessageTag and then message tagged by tag
+// within config Timeout
+func (slacker Slacker) shutdown(tag string, message string, config ShutdownConfig) {
+	timeout := config.Timeout
+	if timeout <= 0 {
+		timeout = DefaultShutdownTimeout
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	flushed := make(chan struct{})
+	go func() {
+		defer close(flushed)
+		for _, queue := range config.Queues {
+			queue.Close()
+		}
+
+		if slacker.DigestWindow > 0 {
+			if err := slacker.FlushDigest(); err != nil {
+				slacker.Log.Errorf("Slacker failed to flush digest %s on shutdown: %s", slacker.MessageTag, err)
+			}
+		}
+	}()
+
+	select {
+	case <-flushed:
+	case <-ctx.Done():
+		slacker.Log.Errorf("Slacker failed to flush messages on shutdown: %s", ctx.Err())
+	}
+
+	slacker.MessageTag = tag
+	slacker.DigestWindow = 0
+
+	// SendContext logs its own failures and the process is going down anyway
+	_ = slacker.SendContext(ctx, message)
+}
@@ -0,0 +1,54 @@
+package slacker
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestShutdownFlushesBeforeMessage(t *testing.T) {
+	hook := newTestHook(t)
+	slacker := newTestSlacker(t, hook)
+	slacker.Frequency = NotifyAlways
+
+	queue, err := slacker.StartQueue(1, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := queue.SendAsync("queued"); err != nil {
+		t.Fatal(err)
+	}
+
+	digest := slacker
+	digest.DigestWindow = time.Hour
+	if err := digest.Send("collected"); err != nil {
+		t.Fatal(err)
+	}
+
+	digest.shutdown("shutdown", "going down", ShutdownConfig{Queues: []*Queue{queue}})
+
+	for _, text := range []string{"queued", "collected", "going down"} {
+		if len(hook.posted(text)) != 1 {
+			t.Fatalf("Message %s is not sent on shutdown: %v", text, hook.payloads)
+		}
+	}
+}
+
+func TestShutdownIsBoundedByTimeout(t *testing.T) {
+	stuck := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-stuck
+	}))
+	defer server.Close()
+	defer close(stuck)
+
+	slacker := newTestSlacker(t, newTestHook(t))
+	slacker.Hook = server.URL
+
+	started := time.Now()
+	slacker.shutdown("shutdown", "going down", ShutdownConfig{Timeout: 100 * time.Millisecond})
+	if elapsed := time.Since(started); elapsed > 5*time.Second {
+		t.Fatalf("Shutdown took %s", elapsed)
+	}
+}
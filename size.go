@@ -0,0 +1,54 @@
+package slacker
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Actions for messages longer than MaxMessageLength
+const (
+	OversizeTruncate int = 0 // Cut the message and note how much was cut
+	OversizeSplit    int = 1 // Post the message in several parts
+	OversizeReject   int = 2 // Return an error without posting
+)
+
+// fitText returns text as one or more parts not longer than MaxMessageLength characters
+func (slacker Slacker) fitText(text string) ([]string, error) {
+	runes := []rune(text)
+	if len(runes) <= slacker.MaxMessageLength {
+		return []string{text}, nil
+	}
+
+	switch slacker.OversizeAction {
+	case OversizeSplit:
+		return splitText(runes, slacker.MaxMessageLength), nil
+	case OversizeReject:
+		return nil, fmt.Errorf("Message is %d characters long, limit is %d", len(runes), slacker.MaxMessageLength)
+	default:
+		suffix := fmt.Sprintf("\n... truncated %d characters", len(runes)-slacker.MaxMessageLength)
+		keep := slacker.MaxMessageLength - len([]rune(suffix))
+		if keep < 0 {
+			keep = 0
+		}
+		return []string{string(runes[:keep]) + suffix}, nil
+	}
+}
+
+// splitText splits runes into parts of at most length runes, preferably at line breaks
+func splitText(runes []rune, length int) (parts []string) {
+	for len(runes) > length {
+		cut := length
+		if newline := strings.LastIndex(string(runes[:length]), "\n"); newline > 0 {
+			cut = len([]rune(string(runes[:length])[:newline])) + 1
+		}
+
+		parts = append(parts, string(runes[:cut]))
+		runes = runes[cut:]
+	}
+
+	if len(runes) > 0 {
+		parts = append(parts, string(runes))
+	}
+
+	return
+}
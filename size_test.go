@@ -0,0 +1,23 @@
+package slacker
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRejectedMessageDoesNotBlockTheTag(t *testing.T) {
+	hook := newTestHook(t)
+	slacker := newTestSlacker(t, hook)
+	slacker.OversizeAction = OversizeReject
+	slacker.MaxMessageLength = 100
+
+	if err := slacker.Send(strings.Repeat("x", 200)); err == nil {
+		t.Fatal("Oversize message is not rejected")
+	}
+	if err := slacker.Send("short"); err != nil {
+		t.Fatal(err)
+	}
+	if len(hook.posted("short")) != 1 {
+		t.Fatalf("Message after a rejected one is not sent: %v", hook.payloads)
+	}
+}
@@ -2,105 +2,568 @@ package slacker
 
 import (
 	"bytes"
+	"context"
+	"crypto/tls"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"io/ioutil"
-	"log"
 	"net"
 	"net/http"
-	"os"
+	"net/url"
+	"sort"
+	"strings"
 	"time"
+	"unicode/utf8"
 )
 
+// Frequency windows are aligned to UTC: NotifyOnceHour windows start at whole
+// UTC hours and NotifyOnceDay windows start at UTC midnight, not local midnight.
 const (
 	NotifyAlways   int = 0
 	NotifyOnceHour int = 1
 	NotifyOnceDay  int = 2
 
+	// AlignCalendar lets one message per calendar hour or day window through
+	AlignCalendar int = 0
+	// AlignRolling lets one message through per hour or day since the last sent one
+	AlignRolling int = 1
+
+	DefaultApiUrl           string = "https://slack.com/api/"
 	DefaultDatabaseFilePath string = "slacker.json"
 	DefaultMessageTag       string = "default_tag"
 	DefaultUsername         string = "Slacker Notifier"
 	DefaultIconEmoji        string = ":ghost:"
+	DefaultMaxMessageLength int    = 40000 // Slack truncates longer texts
+
+	DefaultInitialBackoff time.Duration = time.Second
+	DefaultMaxBackoff     time.Duration = 30 * time.Second
+
+	maxDiagnosticsLength int = 512
+
+	recordPending   string = "pending"
+	recordConfirmed string = "confirmed"
 )
 
+// processStart is used to detect warm-up period
+var processStart = time.Now()
+
 // Slacker sends notification tagged by MessageTag with Frequency
 type Slacker struct {
 	Hook             string
-	Log              *log.Logger
-	IconEmoji        string
+	BotToken         string // Optional, messages are posted with Web API chat.postMessage instead of Hook
+	ApiUrl           string // Optional, Web API base url, DefaultApiUrl by default
+	Log              Logger // Optional, NewStdLogger writing to stdout by default
+	IconEmoji        string // Optional, DefaultIconEmoji if IconUrl is empty too
+	IconUrl          string // Optional, url of an image used as icon unless IconEmoji is set
 	From             string
 	To               []Recipient // Required
 	Frequency        int
+	Alignment        int // AlignCalendar or AlignRolling
 	MessageTag       string
 	DatabaseFilePath string
-	httpClient       *http.Client
+	ReadOnly         bool           // Optional, the database is read but never written
+	MemoryFailover   bool           // Optional, records are kept in memory while the database fails, see GetStoreStats
+	Codec            Codec          // Optional, encodes database lines and Store records, e.g. AesCodec to encrypt them at rest
+	Store            Store          // Optional, keeps records instead of the database file, e.g. MemoryStore
+	Statistics       bool           // Optional, sent and suppressed messages are counted in the database, see Stats
+	Metadata         *SlackMetadata // Optional, lets Slack workflows trigger on the message
+	WarmUp           time.Duration  // Optional, messages are skipped for WarmUp after process start
+	MaxMessageLength int            // Optional, in characters, DefaultMaxMessageLength by default
+	OversizeAction   int            // Optional, OversizeTruncate, OversizeSplit or OversizeReject
+
+	// FrequencyDuration is optional, it overrides Frequency with a window of any length,
+	// e.g. 15 minutes or a week, counted from the Unix epoch like Frequency windows
+	FrequencyDuration time.Duration
+
+	SuppressionNotice bool // Optional, the next sent message tells how many messages were suppressed before it
+	AlertAge          bool // Optional, repeated messages tell how long the tag is firing, until Resolve
+	Acknowledgements  bool // Optional, messages tell who acknowledged the tag with Acknowledge, until Resolve
+	PruneOnSend       bool // Optional, every Send removes expired window entries from the database, see PruneExpired
+	DryRun            bool // Optional, messages are logged and recorded as sent, but nothing is posted
+	ThreadReplies     bool // Optional, with BotToken messages of a tag are replies in the thread of its first message, until Resolve
+	LinkNames         bool // Optional, Slack links plain @name and #channel text, see MentionUser for ids
+	EscapeText        bool // Optional, &, < and > of messages are escaped for Slack, so they are never read as links or mentions
+	DisableMarkdown   bool // Optional, messages are shown without mrkdwn formatting, e.g. *bold* stays as is
+	FlagUnowned       bool // Optional, with Owners messages of tags nobody owns are flagged as unowned
+
+	Labels []string              // Optional, "key=value" labels appended to every message, see With
+	Owners []Owner               // Optional, teams mentioned in messages of the tags they own
+	Levels map[Level]LevelConfig // Optional, settings of messages by severity, see SendWithLevel
+
+	// DigestWindow is optional, messages of a tag are collected instead of sent, and the first message
+	// after DigestWindow since the first collected one sends them all as one digest.
+	// A tag gone silent keeps its digest until FlushDigest or StartDigestFlush sends it.
+	// Frequency does not apply to digests.
+	DigestWindow time.Duration
+
+	// Strict is optional, configuration errors panic instead of being returned,
+	// so they fail loudly in development and tests, see Validate
+	Strict bool
+
+	DNSServers     []string      // Optional, "host" or "host:port" of DNS servers used instead of the system resolver
+	IPVersion      int           // Optional, IPAny, IPv4 or IPv6
+	DialTimeout    time.Duration // Optional, connect timeout, 10 seconds by default
+	ResolveTimeout time.Duration // Optional, DNS lookup timeout apart from DialTimeout, addresses are then tried one by one
+
+	// DialContext is optional, it replaces the default dialer and the resolver settings above,
+	// so connections can go through an SSH tunnel, a bound interface or a Unix socket proxy
+	DialContext func(ctx context.Context, network string, address string) (net.Conn, error)
+
+	AllowedHosts []string  // Optional, hosts Slacker may connect to, e.g. "hooks.slack.com" or "*.slack.com"
+	Recorder     *Recorder // Optional, records or replays HTTP interactions for tests
+
+	Proxy     func(*http.Request) (*url.URL, error) // Optional, e.g. http.ProxyFromEnvironment, no proxy by default
+	TLSConfig *tls.Config                           // Optional, e.g. with RootCAs of a corporate CA
+
+	// HttpClient is optional, it replaces the built-in client and the dial, proxy and TLS settings above.
+	// Redirects are kept within AllowedHosts unless it has its own CheckRedirect.
+	HttpClient *http.Client
+
+	MirrorToDesktop bool              // Optional, also show sent messages as local desktop notifications
+	Twilio          *TwilioConfig     // Optional, SMS fallback for critical messages Slack failed to deliver
+	Statuspage      *StatuspageConfig // Optional, mirrors messages of selected tags to Statuspage incidents
+	Mirrors         []Mirror          // Optional, HTTP endpoints delivered messages are also sent to
+	Events          EventSink         // Optional, receives an Event for every send, suppress and error
+	Sinks           []Notifier        // Optional, other services delivered messages are also sent to
+	Enrichers       []Enricher        // Optional, change messages before they are deduplicated, e.g. ExecEnricher
+
+	// HealthIcons replaces IconEmoji with HealthyIconEmoji or UnhealthyIconEmoji
+	// by the result of HealthFunc or, without it, of the last post to Slack
+	HealthIcons        bool
+	HealthyIconEmoji   string
+	UnhealthyIconEmoji string
+	HealthFunc         func() bool
+
+	// MaxRetries is optional, posts failed with 5xx responses, timeouts or connection errors
+	// are retried up to MaxRetries times waiting InitialBackoff, doubled after every retry
+	// up to MaxBackoff, and randomized by Jitter fraction
+	MaxRetries     int
+	InitialBackoff time.Duration // DefaultInitialBackoff by default
+	MaxBackoff     time.Duration // DefaultMaxBackoff by default
+	Jitter         float64       // From 0 to 1, e.g. 0.2 waits from 80% to 120% of the backoff
+
+	// RateLimit is optional, posts to the same Slack url from this process are limited
+	// to RateLimit per second with bursts of up to RateBurst posts, 1 by default
+	RateLimit float64
+	RateBurst int
+
+	// SlowSendThreshold is optional, a warning is logged and EventSlow emitted when p95 latency
+	// of posts to Slack or another service stays above it for SlowSendPeriod, see GetLatencyStats
+	SlowSendThreshold time.Duration
+	SlowSendPeriod    time.Duration // DefaultSlowSendPeriod by default
+
+	CrashLoopThreshold int           // Optional, see AnnounceStartup
+	CrashLoopWindow    time.Duration // Optional, see AnnounceStartup, DefaultCrashLoopWindow by default
+
+	IdGenerator func() string // Optional, generates message ids, NewUlid by default
+
+	httpClient  *http.Client
+	messageId   string             // Id of the message being sent
+	blocks      []Block            // Blocks of the message being sent
+	attachments []Attachment       // Attachments of the message being sent
+	ctx         context.Context    // Context of the message being sent
+	results     *[]RecipientResult // Collects results of the message being sent by SendAll
+	resolving   bool               // The message being sent is sent by Resolve
+	replaying   bool               // The message being sent is replayed by Replay
 }
 
 type SlackMessage struct {
-	Channel   string `json:"channel"`
-	Username  string `json:"username"`
-	Text      string `json:"text"`
-	IconEmoji string `json:"icon_emoji"`
+	Channel     string         `json:"channel"`
+	Username    string         `json:"username"`
+	Text        string         `json:"text"`
+	IconEmoji   string         `json:"icon_emoji,omitempty"`
+	IconUrl     string         `json:"icon_url,omitempty"`
+	Metadata    *SlackMetadata `json:"metadata,omitempty"`
+	Blocks      []Block        `json:"blocks,omitempty"`
+	Attachments []Attachment   `json:"attachments,omitempty"`
+	ThreadTs    string         `json:"thread_ts,omitempty"`
+	LinkNames   bool           `json:"link_names,omitempty"`
+	Mrkdwn      *bool          `json:"mrkdwn,omitempty"` // Slack formats text with mrkdwn if nil
+}
+
+// SlackMetadata holds Slack message metadata event type and payload
+type SlackMetadata struct {
+	EventType    string                 `json:"event_type"`
+	EventPayload map[string]interface{} `json:"event_payload"`
 }
 
 // Recipient holds Channel and Username
 type Recipient struct {
-	Channel  string
-	Username string
+	Channel   string
+	Username  string
+	IconEmoji string // Optional, replaces IconEmoji of Slacker for this recipient
+	IconUrl   string // Optional, replaces the icon of Slacker with an image for this recipient
+	From      string // Optional, replaces From of Slacker for this recipient
+}
+
+// persona returns slackMessage with icon and username of recipient if set
+func (recipient Recipient) persona(slackMessage SlackMessage) SlackMessage {
+	// Slack prefers icon_emoji over icon_url, so only one of them is posted
+	if recipient.IconUrl != "" {
+		slackMessage.IconUrl = recipient.IconUrl
+		slackMessage.IconEmoji = ""
+	}
+
+	if recipient.IconEmoji != "" {
+		slackMessage.IconEmoji = recipient.IconEmoji
+		slackMessage.IconUrl = ""
+	}
+
+	if recipient.From != "" {
+		slackMessage.Username = recipient.From
+	}
+
+	return slackMessage
+}
+
+func (recipient Recipient) id() string {
+	return strings.TrimSpace(recipient.Channel + " " + recipient.Username)
 }
 
 // Send message with subject
 func (slacker Slacker) Send(message string) error {
+	_, err := slacker.SendWithId(message)
+	return err
+}
+
+// SendContext sends message like Send. Requests to Slack and other services
+// respect ctx deadline and database operations are not started once ctx is done.
+// Sinks and Enrichers get ctx if they are ContextNotifier and ContextEnricher,
+// the others, e.g. EmailSink, finish their work whatever ctx is.
+func (slacker Slacker) SendContext(ctx context.Context, message string) error {
+	_, err := slacker.sendContext(ctx, message)
+	return err
+}
+
+// SendWithId sends message like Send and returns its id, which is recorded
+// in the database and events. A suppressed message gets the id of the message
+// which suppressed it.
+func (slacker Slacker) SendWithId(message string) (id string, err error) {
+	return slacker.sendContext(context.Background(), message)
+}
+
+func (slacker Slacker) sendContext(ctx context.Context, message string) (id string, err error) {
 	if err := slacker.setDefaults(); err != nil {
-		return fmt.Errorf("Slacker failed to send message: %s", err)
+		return "", fmt.Errorf("Slacker failed to send message: %s", err)
+	}
+
+	slacker.ctx = ctx
+
+	slacker.messageId = slacker.newMessageId()
+
+	if slacker.inWarmUp() {
+		slacker.Log.Debugf("Skip message %s during warm-up: %s", slacker.MessageTag, message)
+		slacker.emit(EventSuppress, "", message, nil)
+		return "", nil
+	}
+
+	now := time.Now()
+	message = slacker.prepare(message, now)
+
+	if slacker.DigestWindow > 0 {
+		return slacker.sendDigest(message, now)
+	}
+
+	if slacker.isAlways() {
+		return slacker.messageId, slacker.deliver(message, "", nil)
+	}
+
+	if slacker.PruneOnSend {
+		if _, err := slacker.prune(now, false); err != nil {
+			slacker.Log.Errorf("Slacker failed to prune database: %s", err)
+		}
+	}
+
+	hash := slacker.getHashAt(now)
+	record, found, err := slacker.getRecord(hash)
+	if err != nil {
+		slacker.Log.Errorf("Slacker failed to send message: %s", err)
+		slacker.emit(EventError, hash, message, err)
+		return "", err
+	}
+
+	if found && !slacker.isDue(*record, found, now) {
+		slacker.Log.Debugf("Skip message %s: %s", hash, message)
+		slacker.emit(EventSuppress, hash, message, nil)
+		if slacker.SuppressionNotice {
+			slacker.recordSuppressed(message, now)
+		}
+		return record.MessageId, nil
+	}
+
+	if found && record.State == recordPending && isInFlight(*record, now) {
+		slacker.Log.Debugf("Skip message %s being sent by another process: %s", hash, message)
+		slacker.emit(EventSuppress, hash, message, nil)
+		return record.MessageId, nil
+	}
+
+	claim := &dbRecord{Message: message, State: recordPending, Time: now, MessageId: slacker.messageId}
+	if found {
+		claim.Version = record.Version
+	}
+	if found && record.State == recordPending {
+		slacker.Log.Infof("Resume pending message %s, already delivered to %v: %s", hash, record.Delivered, message)
+		claim.Delivered = record.Delivered
+		if record.MessageId != "" {
+			claim.MessageId = record.MessageId
+			slacker.messageId = record.MessageId
+		}
+	}
+	record = claim
+
+	err = slacker.putRecord(hash, record)
+	if err == errConflict {
+		slacker.Log.Debugf("Skip message %s sent by another process: %s", hash, message)
+		slacker.emit(EventSuppress, hash, message, nil)
+		return "", nil
+	}
+	if err != nil {
+		slacker.Log.Errorf("Slacker failed to send message: %s", err)
+		slacker.emit(EventError, hash, message, err)
+		return "", err
 	}
 
-	hash := slacker.getHash()
-	if !slacker.needToSend(hash) {
-		slacker.Log.Printf("Skip message %s: %s", hash, message)
-		return nil
+	if slacker.SuppressionNotice {
+		message = slacker.takeSuppressionNotice() + message
 	}
 
+	return slacker.messageId, slacker.deliver(message, hash, record)
+}
+
+// prepare changes message with Enrichers and annotates it with the alert age
+// and the acknowledgement of the tag at now before it is deduplicated
+func (slacker Slacker) prepare(message string, now time.Time) string {
+	message = slacker.enrich(message)
+
+	if slacker.AlertAge {
+		message = slacker.recordSeen(now).annotate(message, now)
+	}
+
+	if slacker.Acknowledgements {
+		message = slacker.acknowledgement().annotate(message)
+	}
+
+	return message
+}
+
+// deliver sends message to recipients not yet delivered in record and confirms the record.
+// If record is nil the delivery is not recorded.
+func (slacker Slacker) deliver(message string, hash string, record *dbRecord) (err error) {
+	// A failed delivery releases record until it is confirmed,
+	// so the next Send retries the message instead of skipping it as in flight
+	claimed := record
+	defer func() {
+		if err != nil {
+			slacker.releaseRecord(hash, claimed)
+		}
+	}()
+
+	message = slacker.labeled(message)
+
 	slackMessage := SlackMessage{
-		IconEmoji: slacker.IconEmoji,
-		Username:  slacker.From,
+		IconEmoji:   slacker.IconEmoji,
+		Username:    slacker.From,
+		Metadata:    slacker.Metadata,
+		Blocks:      slacker.blocks,
+		Attachments: slacker.attachments,
+		LinkNames:   slacker.LinkNames,
 	}
 
+	if slacker.DisableMarkdown {
+		mrkdwn := false
+		slackMessage.Mrkdwn = &mrkdwn
+	}
+
+	if slackMessage.IconEmoji == "" {
+		slackMessage.IconUrl = slacker.IconUrl
+	}
+
+	if slacker.HealthIcons {
+		slackMessage.IconEmoji = slacker.healthIcon()
+		slackMessage.IconUrl = ""
+	}
+
+	owner := slacker.ownerMention()
+
+	var failures MultiError
+	delivered := 0
 	for _, recipient := range slacker.To {
+		if record != nil && record.isDelivered(recipient) {
+			slacker.addResult(recipient, nil)
+			delivered++
+			continue
+		}
+
+		texts, err := slacker.fitText(strings.TrimSpace(owner+" "+recipient.Username) + " " + slacker.escape(message))
+		if err != nil {
+			slacker.Log.Errorf("Slacker failed to send message: %s", err)
+			slacker.emit(EventError, hash, message, err)
+			if slacker.results == nil {
+				slacker.sendSmsFallback(message)
+				slacker.recordHealth(err)
+				return err
+			}
+			failures = append(failures, slacker.addResult(recipient, err))
+			continue
+		}
+
+		// Blocks carry the content, the text is only a notification fallback
+		if len(slackMessage.Blocks) > 0 && len(texts) > 1 {
+			texts = texts[:1]
+		}
+
+		// Attachments carry their own fallback, the text only mentions the owner and the recipient
+		if len(slackMessage.Attachments) > 0 {
+			texts = []string{strings.TrimSpace(owner + " " + recipient.Username)}
+		}
+
 		slackMessage.Channel = recipient.Channel
-		slackMessage.Text = recipient.Username + " " + message
+		slackMessage.ThreadTs = slacker.threadTs(recipient)
+		response, err := slacker.post(recipient.persona(slackMessage), texts, message)
+		if err != nil {
+			slacker.Log.Errorf("Slacker failed to send message: %s", err)
+			slacker.emit(EventError, hash, message, err)
+			if slacker.results == nil {
+				slacker.sendSmsFallback(message)
+				slacker.recordHealth(err)
+				return err
+			}
+			failures = append(failures, slacker.addResult(recipient, err))
+			continue
+		}
+
+		slacker.addResult(recipient, nil)
+		delivered++
+
+		if slackMessage.ThreadTs == "" {
+			slacker.startThread(recipient, response)
+		}
 
-		response, err := slacker.send(slackMessage)
+		if record == nil {
+			continue
+		}
+
+		record.Delivered = append(record.Delivered, recipient.id())
+		err = slacker.putRecord(hash, record)
+		if err == errConflict {
+			slacker.Log.Infof("Stop delivery of message %s taken over by another process: %s", hash, message)
+			return nil
+		}
 		if err != nil {
-			slacker.Log.Printf("Slacker failed to send message: %s", err)
+			slacker.Log.Errorf("Slacker failed to send message: %s", err)
+			slacker.emit(EventError, hash, message, err)
 			return err
 		}
+	}
 
-		slacker.Log.Printf("Send message %s: %s %s", slacker.MessageTag, message, response)
+	// SendAll keeps the message of failed recipients as sent if any recipient got it
+	if len(failures) > 0 && delivered == 0 {
+		slacker.sendSmsFallback(message)
+		slacker.recordHealth(failures)
+		return failures
 	}
 
-	err := slacker.addToDb(hash, message)
-	if err != nil {
-		slacker.Log.Printf("Slacker failed to send message: %s", err)
-		return err
+	slacker.emit(EventSend, hash, message, nil)
+	if len(failures) > 0 {
+		slacker.recordHealth(failures)
+	} else {
+		slacker.recordHealth(nil)
+	}
+
+	// Dry run messages stay within the log, the events and the database
+	if !slacker.DryRun {
+		if slacker.MirrorToDesktop {
+			slacker.mirrorToDesktop(message)
+		}
+
+		slacker.updateStatuspage(message)
+		slacker.sendToMirrors(message)
+		slacker.notifySinks(message)
+	}
+
+	// Every recipient got the message, releasing the record would send it again
+	claimed = nil
+
+	if record != nil {
+		record.State = recordConfirmed
+		record.Delivered = nil
+		record.Time = time.Now()
+		record.DryRun = slacker.DryRun
+		if err := slacker.putRecord(hash, record); err != nil {
+			slacker.Log.Errorf("Slacker failed to send message: %s", err)
+			slacker.emit(EventError, hash, message, err)
+			return err
+		}
+	}
+
+	if len(failures) > 0 {
+		return failures
 	}
 
 	return nil
 }
 
+// post sends texts of message to the channel of slackMessage one by one
+// and returns the response to the first text
+func (slacker Slacker) post(slackMessage SlackMessage, texts []string, message string) (first string, err error) {
+	for i, text := range texts {
+		slackMessage.Text = text
+		if err := LintSlackMessage(slackMessage); err != nil {
+			return "", err
+		}
+
+		response, err := slacker.sendWithRetries(slackMessage)
+		if err != nil {
+			return "", err
+		}
+
+		slacker.Log.Infof("Send message %s: %s %s", slacker.MessageTag, message, response)
+		if i == 0 {
+			first = response
+		}
+	}
+
+	return first, nil
+}
+
+// SlackPayload returns the request body posted to Slack for message
+func SlackPayload(message SlackMessage) ([]byte, error) {
+	return json.Marshal(message)
+}
+
 func (slacker *Slacker) send(message SlackMessage) (response string, err error) {
-	payload, err := json.Marshal(message)
+	payload, err := SlackPayload(message)
 	if err != nil {
 		return "", err
 	}
 
+	if slacker.DryRun {
+		slacker.Log.Infof("Dry run, would send to %s: %s", message.Channel, payload)
+		return "dry run", nil
+	}
+
+	if err := slacker.waitRateLimit(); err != nil {
+		return "", err
+	}
+
 	if slacker.httpClient == nil {
 		slacker.setHttpClient()
 	}
 
-	raw_response, err := slacker.httpClient.Post(slacker.Hook, "string", bytes.NewBuffer(payload))
+	request, err := http.NewRequest(http.MethodPost, slacker.postUrl(), bytes.NewBuffer(payload))
+	if err != nil {
+		return "", err
+	}
+	request.Header.Set("Content-Type", "application/json; charset=utf-8")
+	if slacker.BotToken != "" {
+		request.Header.Set("Authorization", "Bearer "+slacker.BotToken)
+	}
+
+	started := time.Now()
+	raw_response, err := slacker.httpClient.Do(request.WithContext(slacker.context()))
+	slacker.observeLatency("Slack", time.Since(started))
 	if raw_response != nil {
 		defer slacker.ioClose(raw_response.Body)
 	}
@@ -114,24 +577,98 @@ func (slacker *Slacker) send(message SlackMessage) (response string, err error)
 		return "", err
 	}
 
-	response = string(byte_response)
-	if response != "ok" {
-		return "", fmt.Errorf("Response from Slack: %s", response)
+	response = strings.TrimSpace(string(byte_response))
+	if raw_response.StatusCode < 200 || raw_response.StatusCode > 299 || isHtml(raw_response) {
+		return "", &ResponseError{
+			StatusCode: raw_response.StatusCode,
+			Status:     raw_response.Status,
+			Header:     raw_response.Header,
+			Body:       response,
+		}
+	}
+
+	if slacker.BotToken != "" {
+		return response, checkApiResponse(response)
 	}
 
 	return
 }
 
-func (slacker *Slacker) setDefaults() error {
-	if slacker.Hook == "" {
-		return errors.New("Web hook url is not set")
+// ResponseError is an unsuccessful response from Slack
+type ResponseError struct {
+	StatusCode int
+	Status     string
+	Header     http.Header
+	Body       string
+}
+
+func (err *ResponseError) Error() string {
+	return fmt.Sprintf("Response from Slack: %s, headers: %s, body: %s",
+		err.Status, headersSnippet(err.Header), truncate(err.Body, maxDiagnosticsLength))
+}
+
+// isHtml detects error pages returned by proxies in front of Slack
+func isHtml(response *http.Response) bool {
+	return strings.HasPrefix(response.Header.Get("Content-Type"), "text/html")
+}
+
+func headersSnippet(header http.Header) string {
+	var names []string
+	for name := range header {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var pairs []string
+	for _, name := range names {
+		pairs = append(pairs, name+": "+strings.Join(header[name], ", "))
+	}
+
+	return truncate(strings.Join(pairs, "; "), maxDiagnosticsLength)
+}
+
+func truncate(s string, length int) string {
+	if len(s) <= length {
+		return s
+	}
+
+	// Cut on a rune boundary, so a multi-byte character is never split
+	for length > 0 && !utf8.RuneStart(s[length]) {
+		length--
+	}
+
+	return s[:length] + "..."
+}
+
+func (slacker *Slacker) setDefaults() (err error) {
+	defer func() {
+		if err != nil && slacker.Strict {
+			panic(fmt.Sprintf("Slacker is misconfigured: %s", err))
+		}
+	}()
+
+	if slacker.Hook == "" && slacker.BotToken == "" {
+		return errors.New("Web hook url or bot token is not set")
+	}
+
+	if slacker.ApiUrl == "" {
+		slacker.ApiUrl = DefaultApiUrl
+	}
+
+	if err := slacker.checkEgress(slacker.postUrl()); err != nil {
+		return fmt.Errorf("Slack url is not allowed: %s", err)
 	}
 
 	if len(slacker.To) == 0 {
 		return errors.New("Recipients are not set")
 	}
 
-	if slacker.IconEmoji == "" {
+	// Window hashes have a resolution of a second
+	if slacker.FrequencyDuration > 0 && slacker.FrequencyDuration < time.Second {
+		return fmt.Errorf("Frequency duration %s is shorter than a second", slacker.FrequencyDuration)
+	}
+
+	if slacker.IconEmoji == "" && slacker.IconUrl == "" {
 		slacker.IconEmoji = DefaultIconEmoji
 	}
 
@@ -140,7 +677,7 @@ func (slacker *Slacker) setDefaults() error {
 	}
 
 	if slacker.Log == nil {
-		slacker.Log = log.New(os.Stdout, DefaultUsername+" ", log.LstdFlags)
+		slacker.Log = defaultLogger()
 	}
 
 	if slacker.MessageTag == "" {
@@ -151,133 +688,171 @@ func (slacker *Slacker) setDefaults() error {
 		slacker.DatabaseFilePath = DefaultDatabaseFilePath
 	}
 
-	return nil
-}
+	if slacker.HealthyIconEmoji == "" {
+		slacker.HealthyIconEmoji = DefaultHealthyIconEmoji
+	}
 
-func (slacker Slacker) needToSend(hash string) bool {
-	if slacker.Frequency == NotifyAlways {
-		return true
+	if slacker.UnhealthyIconEmoji == "" {
+		slacker.UnhealthyIconEmoji = DefaultUnhealthyIconEmoji
 	}
 
-	if slacker.inDb(hash) {
-		return false
+	if slacker.InitialBackoff <= 0 {
+		slacker.InitialBackoff = DefaultInitialBackoff
+	}
+
+	if slacker.MaxBackoff <= 0 {
+		slacker.MaxBackoff = DefaultMaxBackoff
+	}
+
+	if slacker.MaxMessageLength <= 0 {
+		slacker.MaxMessageLength = DefaultMaxMessageLength
+	}
+
+	if slacker.CrashLoopThreshold > 0 && slacker.CrashLoopWindow <= 0 {
+		slacker.CrashLoopWindow = DefaultCrashLoopWindow
+	}
+
+	if slacker.Metadata != nil && slacker.Metadata.EventType == "" {
+		return errors.New("Metadata event type is not set")
 	}
 
-	return true
+	return nil
 }
 
 func (slacker Slacker) getHash() (hash string) {
-	t := time.Now()
+	return slacker.getHashAt(time.Now())
+}
+
+func (slacker Slacker) getHashAt(t time.Time) (hash string) {
+	if !slacker.isAlways() && slacker.Alignment == AlignRolling {
+		hash = "rolling:" + slacker.MessageTag
+		return
+	}
+
+	if slacker.FrequencyDuration > 0 {
+		hash = windowStart(t, slacker.FrequencyDuration).Format("2006-01-02T150405Z") + "/" + slacker.FrequencyDuration.String() + ":" + slacker.MessageTag
+		return
+	}
 
 	if slacker.Frequency == NotifyOnceHour {
-		hash = t.Format("2006-01-02-15") + ":" + slacker.MessageTag
+		hash = windowStart(t, time.Hour).Format("2006-01-02-15") + ":" + slacker.MessageTag
 		return
 	}
 
 	if slacker.Frequency == NotifyOnceDay {
-		hash = t.Format("2006-01-02") + ":" + slacker.MessageTag
+		hash = windowStart(t, 24*time.Hour).Format("2006-01-02") + ":" + slacker.MessageTag
 		return
 	}
 
 	return
 }
 
-func (slacker Slacker) addToDb(hash string, subject string) error {
-	db, err := slacker.loadDb()
-	if err != nil {
-		return fmt.Errorf("Slacker failed to add %s:%s to database: %s", hash, subject, err)
-	}
+func (slacker Slacker) inWarmUp() bool {
+	return time.Since(processStart) < slacker.WarmUp
+}
 
-	db[hash] = subject
+// isAlways reports whether every message is sent without deduplication
+func (slacker Slacker) isAlways() bool {
+	return slacker.Frequency == NotifyAlways && slacker.FrequencyDuration <= 0
+}
 
-	err = slacker.saveDb(db)
-	if err != nil {
-		return fmt.Errorf("Slacker failed to add %s to database: %s", hash, err)
+// window returns the length of the FrequencyDuration or Frequency window
+func (slacker Slacker) window() time.Duration {
+	if slacker.FrequencyDuration > 0 {
+		return slacker.FrequencyDuration
 	}
 
-	return nil
-}
-
-func (slacker Slacker) inDb(hash string) bool {
-	db, err := slacker.loadDb()
-	if err != nil {
-		slacker.Log.Printf("Slacker failed to load database: %s", err)
-		return false
+	if slacker.Frequency == NotifyOnceHour {
+		return time.Hour
 	}
 
-	if _, ok := db[hash]; ok == true {
-		return true
+	if slacker.Frequency == NotifyOnceDay {
+		return 24 * time.Hour
 	}
 
-	return false
+	return 0
 }
 
-func (slacker Slacker) saveDb(db map[string]string) (err error) {
-	dbFile, err := os.OpenFile(slacker.DatabaseFilePath, os.O_WRONLY, os.ModeExclusive)
-	if err != nil {
-		return fmt.Errorf("Slacker failed to save database: Failed to open database file: %s", err)
-	}
-	defer dbFile.Close()
-
-	err = json.NewEncoder(dbFile).Encode(db)
-	if err != nil {
-		return fmt.Errorf("Slacker failed to save database: Failed to encode json to file: %s", err)
+// isExpired reports whether a rolling window started by the record is over.
+// Calendar records never expire, a new window gets a new hash instead.
+func (slacker Slacker) isExpired(record dbRecord, now time.Time) bool {
+	if slacker.Alignment != AlignRolling {
+		return false
 	}
 
-	return
+	return now.Sub(record.Time) >= slacker.window()
 }
 
-func (slacker Slacker) loadDb() (db map[string]string, err error) {
-	dbFile, err := os.Open(slacker.DatabaseFilePath)
-	if err != nil {
-		dbFile, err = slacker.createDb()
-		if err != nil {
-			return nil, fmt.Errorf("Slacker failed to load database: %s", err)
-		}
+// windowStart returns the UTC start of the window of given length containing t.
+// Windows are counted from the Unix epoch, so every window has the same length
+// whatever the local time zone is: a DST change never makes a 23 or 25 hour day
+// and the repeated local hour after a fall back is not merged into one window.
+func windowStart(t time.Time, window time.Duration) time.Time {
+	seconds := int64(window / time.Second)
+	unix := t.Unix()
+	offset := unix % seconds
+	if offset < 0 {
+		offset += seconds
 	}
-	defer dbFile.Close()
 
-	db = make(map[string]string)
-	err = json.NewDecoder(dbFile).Decode(&db)
-	if err != nil {
-		return nil, fmt.Errorf("Slacker failed to load database: %s", err)
+	return time.Unix(unix-offset, 0).UTC()
+}
+
+// context returns the context of the message being sent
+func (slacker Slacker) context() context.Context {
+	if slacker.ctx == nil {
+		return context.Background()
 	}
 
-	return
+	return slacker.ctx
 }
 
-func (slacker Slacker) createDb() (dbFile *os.File, err error) {
-	dbFile, err = os.Create(slacker.DatabaseFilePath)
-	if err != nil {
-		return nil, fmt.Errorf("Slacker failed to create database file %s: %s", slacker.DatabaseFilePath, err)
+func (slacker *Slacker) setHttpClient() {
+	if slacker.HttpClient != nil {
+		client := *slacker.HttpClient
+		if client.CheckRedirect == nil {
+			client.CheckRedirect = slacker.checkRedirect
+		}
+		slacker.httpClient = &client
+
+		if slacker.Recorder != nil {
+			next := client.Transport
+			if next == nil {
+				next = http.DefaultTransport
+			}
+			slacker.Recorder.setNext(next)
+			slacker.httpClient.Transport = slacker.Recorder
+		}
+		return
 	}
-	err = dbFile.Truncate(0)
-	if err != nil {
-		return nil, fmt.Errorf("Slacker failed to create database file %s: %s", slacker.DatabaseFilePath, err)
+
+	dialContext := slacker.dialer().DialContext
+	if slacker.hasResolverSettings() {
+		dialContext = slacker.dialContext
 	}
-	_, err = dbFile.Write([]byte("{}"))
-	if err != nil {
-		return nil, fmt.Errorf("Slacker failed to create database file %s: %s", slacker.DatabaseFilePath, err)
+	if slacker.DialContext != nil {
+		dialContext = slacker.DialContext
 	}
-	return
-}
 
-func (slacker *Slacker) setHttpClient() {
 	tr := &http.Transport{
-		Dial: (&net.Dialer{
-			Timeout:   10 * time.Second,
-			KeepAlive: 10 * time.Second,
-		}).Dial,
+		Proxy:                 slacker.Proxy,
+		DialContext:           dialContext,
+		TLSClientConfig:       slacker.TLSConfig,
 		TLSHandshakeTimeout:   10 * time.Second,
 		ResponseHeaderTimeout: time.Second * 10,
 		MaxIdleConnsPerHost:   128,
 	}
-	slacker.httpClient = &http.Client{Transport: tr}
+	slacker.httpClient = &http.Client{Transport: tr, CheckRedirect: slacker.checkRedirect}
+
+	if slacker.Recorder != nil {
+		slacker.Recorder.setNext(tr)
+		slacker.httpClient.Transport = slacker.Recorder
+	}
 }
 
 func (slacker *Slacker) ioClose(c io.Closer) {
 	err := c.Close()
 	if err != nil {
-		slacker.Log.Printf("Failed to close resource: %s", err)
+		slacker.Log.Errorf("Failed to close resource: %s", err)
 	}
 }
@@ -0,0 +1,136 @@
+package slacker
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// testHook is a Slack webhook recording posted payloads, failing while fail is set
+type testHook struct {
+	*httptest.Server
+	mutex    sync.Mutex
+	payloads []string
+	fail     bool
+}
+
+func newTestHook(t *testing.T) *testHook {
+	hook := &testHook{}
+	hook.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+
+		hook.mutex.Lock()
+		defer hook.mutex.Unlock()
+		if hook.fail {
+			http.Error(w, "fail", http.StatusInternalServerError)
+			return
+		}
+		hook.payloads = append(hook.payloads, string(body))
+		w.Write([]byte("ok"))
+	}))
+	t.Cleanup(hook.Close)
+	return hook
+}
+
+func (hook *testHook) setFail(fail bool) {
+	hook.mutex.Lock()
+	defer hook.mutex.Unlock()
+	hook.fail = fail
+}
+
+// posted returns payloads containing text
+func (hook *testHook) posted(text string) (posted []string) {
+	hook.mutex.Lock()
+	defer hook.mutex.Unlock()
+	for _, payload := range hook.payloads {
+		if strings.Contains(payload, text) {
+			posted = append(posted, payload)
+		}
+	}
+	return posted
+}
+
+// newTestSlacker returns Slacker posting to hook with a database in a temporary directory
+func newTestSlacker(t *testing.T, hook *testHook) Slacker {
+	return Slacker{
+		Hook:             hook.URL,
+		To:               []Recipient{{Channel: "#alerts"}},
+		Frequency:        NotifyOnceHour,
+		MessageTag:       "test",
+		DatabaseFilePath: filepath.Join(t.TempDir(), "slacker.json"),
+		Log:              testLogger{t},
+	}
+}
+
+// testLogger writes Slacker logs to the test log
+type testLogger struct {
+	t *testing.T
+}
+
+func (logger testLogger) Debugf(format string, args ...interface{}) { logger.t.Logf(format, args...) }
+func (logger testLogger) Infof(format string, args ...interface{})  { logger.t.Logf(format, args...) }
+func (logger testLogger) Errorf(format string, args ...interface{}) { logger.t.Logf(format, args...) }
+
+func TestSubSecondFrequencyDurationIsRejected(t *testing.T) {
+	hook := newTestHook(t)
+	slacker := newTestSlacker(t, hook)
+	slacker.FrequencyDuration = 500 * time.Millisecond
+
+	if err := slacker.Validate(); err == nil {
+		t.Fatal("Validate accepted a sub-second frequency duration")
+	}
+	if err := slacker.Send("message"); err == nil {
+		t.Fatal("Send accepted a sub-second frequency duration")
+	}
+}
+
+func TestWindowsAcrossDaylightSavingTime(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skipf("Time zone database is not available: %s", err)
+	}
+
+	slacker := Slacker{Frequency: NotifyOnceHour, MessageTag: "tag"}
+
+	// 2021-10-31 02:30 happens twice in Berlin, an hour apart
+	first := time.Date(2021, 10, 31, 0, 30, 0, 0, time.UTC).In(berlin)
+	second := first.Add(time.Hour)
+	if first.Hour() != second.Hour() {
+		t.Fatalf("Test times are not the repeated local hour: %s, %s", first, second)
+	}
+	if slacker.getHashAt(first) == slacker.getHashAt(second) {
+		t.Fatalf("Repeated local hour after fall back is one window: %s", slacker.getHashAt(first))
+	}
+
+	// The day of spring forward has 23 local hours, but its window is 24 hours of UTC
+	slacker.Frequency = NotifyOnceDay
+	springForward := time.Date(2021, 3, 28, 12, 0, 0, 0, berlin)
+	if got := windowStart(springForward, 24*time.Hour); !got.Equal(time.Date(2021, 3, 28, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("Day window of %s starts at %s", springForward, got)
+	}
+	if got := slacker.getHashAt(springForward); got != "2021-03-28:tag" {
+		t.Fatalf("Day hash of %s is %s", springForward, got)
+	}
+}
+
+func TestWindowStartBeforeUnixEpoch(t *testing.T) {
+	for _, test := range []struct {
+		t      time.Time
+		window time.Duration
+		start  time.Time
+	}{
+		{time.Date(1969, 12, 31, 23, 59, 59, 0, time.UTC), time.Hour, time.Date(1969, 12, 31, 23, 0, 0, 0, time.UTC)},
+		{time.Date(1969, 12, 31, 12, 0, 0, 0, time.UTC), 24 * time.Hour, time.Date(1969, 12, 31, 0, 0, 0, 0, time.UTC)},
+		{time.Date(1970, 1, 1, 0, 0, 0, 0, time.UTC), time.Hour, time.Date(1970, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{time.Date(1969, 12, 31, 23, 50, 0, 0, time.UTC), 15 * time.Minute, time.Date(1969, 12, 31, 23, 45, 0, 0, time.UTC)},
+	} {
+		if got := windowStart(test.t, test.window); !got.Equal(test.start) {
+			t.Errorf("Window of %s containing %s starts at %s, expected %s", test.window, test.t, got, test.start)
+		}
+	}
+}
@@ -0,0 +1,164 @@
+// Package sqlitestore keeps Slacker records and the history of sent, suppressed
+// and failed messages in SQLite, so deliveries can be audited with History.
+// It works with any database/sql SQLite driver, e.g. modernc.org/sqlite or github.com/mattn/go-sqlite3,
+// so it has no dependencies itself and the application pins the driver it imports.
+package sqlitestore
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/oneumyvakin/slacker"
+)
+
+const schema = `
+CREATE TABLE IF NOT EXISTS records (
+	key TEXT PRIMARY KEY,
+	value BLOB NOT NULL,
+	version INTEGER NOT NULL,
+	expires INTEGER NOT NULL
+);
+CREATE TABLE IF NOT EXISTS history (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	tag TEXT NOT NULL,
+	hash TEXT NOT NULL,
+	message_id TEXT NOT NULL,
+	message TEXT NOT NULL,
+	time INTEGER NOT NULL,
+	recipients TEXT NOT NULL,
+	status TEXT NOT NULL,
+	error TEXT NOT NULL
+);
+CREATE INDEX IF NOT EXISTS history_tag_time ON history (tag, time);
+`
+
+// Store implements slacker.Store and slacker.HistoryRecorder on SQLite
+type Store struct {
+	db *sql.DB
+}
+
+// New returns Store on db opened with a SQLite driver and creates its tables
+func New(db *sql.DB) (*Store, error) {
+	if _, err := db.Exec(schema); err != nil {
+		return nil, fmt.Errorf("Failed to create SQLite tables: %s", err)
+	}
+
+	return &Store{db: db}, nil
+}
+
+// Get implements slacker.Store
+func (store *Store) Get(key string) (record slacker.StoreRecord, found bool, err error) {
+	var expires int64
+	err = store.db.QueryRow(`SELECT value, version, expires FROM records WHERE key = ?`, key).
+		Scan(&record.Value, &record.Version, &expires)
+	if err == sql.ErrNoRows {
+		return record, false, nil
+	}
+	if err != nil {
+		return record, false, fmt.Errorf("Failed to get record %s from SQLite: %s", key, err)
+	}
+
+	if expires != 0 {
+		record.Expires = time.Unix(0, expires)
+		if !time.Now().Before(record.Expires) {
+			return slacker.StoreRecord{}, false, nil
+		}
+	}
+
+	record.Key = key
+	return record, true, nil
+}
+
+// Put implements slacker.Store, all records are written in one transaction
+func (store *Store) Put(records []slacker.StoreRecord) (conflicts map[string]bool, err error) {
+	tx, err := store.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("Failed to begin SQLite transaction: %s", err)
+	}
+	defer tx.Rollback()
+
+	now := time.Now().UnixNano()
+	if _, err := tx.Exec(`DELETE FROM records WHERE expires != 0 AND expires <= ?`, now); err != nil {
+		return nil, fmt.Errorf("Failed to delete expired records from SQLite: %s", err)
+	}
+
+	conflicts = make(map[string]bool)
+	for _, record := range records {
+		var version int
+		err := tx.QueryRow(`SELECT version FROM records WHERE key = ?`, record.Key).Scan(&version)
+		if err != nil && err != sql.ErrNoRows {
+			return nil, fmt.Errorf("Failed to get record %s from SQLite: %s", record.Key, err)
+		}
+
+		if !record.Force && version != record.Version {
+			conflicts[record.Key] = true
+			continue
+		}
+
+		var expires int64
+		if !record.Expires.IsZero() {
+			expires = record.Expires.UnixNano()
+		}
+
+		_, err = tx.Exec(`INSERT INTO records (key, value, version, expires) VALUES (?, ?, ?, ?)
+			ON CONFLICT (key) DO UPDATE SET value = excluded.value, version = excluded.version, expires = excluded.expires`,
+			record.Key, record.Value, version+1, expires)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to put record %s to SQLite: %s", record.Key, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("Failed to commit SQLite transaction: %s", err)
+	}
+
+	return conflicts, nil
+}
+
+// RecordHistory implements slacker.HistoryRecorder
+func (store *Store) RecordHistory(record slacker.HistoryRecord) error {
+	_, err := store.db.Exec(`INSERT INTO history (tag, hash, message_id, message, time, recipients, status, error)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+		record.MessageTag, record.Hash, record.MessageId, record.Text, record.Time.UnixNano(),
+		strings.Join(record.Recipients, "\n"), record.Status, record.Error)
+	if err != nil {
+		return fmt.Errorf("Failed to add history to SQLite: %s", err)
+	}
+
+	return nil
+}
+
+// History returns messages of tag, any if empty, sent, suppressed or failed at or after since, oldest first
+func (store *Store) History(tag string, since time.Time) ([]slacker.HistoryRecord, error) {
+	rows, err := store.db.Query(`SELECT tag, hash, message_id, message, time, recipients, status, error FROM history
+		WHERE (? = '' OR tag = ?) AND time >= ? ORDER BY time, id`, tag, tag, since.UnixNano())
+	if err != nil {
+		return nil, fmt.Errorf("Failed to query history from SQLite: %s", err)
+	}
+	defer rows.Close()
+
+	var history []slacker.HistoryRecord
+	for rows.Next() {
+		var record slacker.HistoryRecord
+		var nanos int64
+		var recipients string
+		err := rows.Scan(&record.MessageTag, &record.Hash, &record.MessageId, &record.Text, &nanos, &recipients, &record.Status, &record.Error)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to read history from SQLite: %s", err)
+		}
+
+		record.Time = time.Unix(0, nanos)
+		if recipients != "" {
+			record.Recipients = strings.Split(recipients, "\n")
+		}
+		history = append(history, record)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("Failed to read history from SQLite: %s", err)
+	}
+
+	return history, nil
+}
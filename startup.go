@@ -0,0 +1,115 @@
+package slacker
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+	"runtime/debug"
+	"sort"
+	"strings"
+	"time"
+)
+
+const DefaultCrashLoopWindow time.Duration = 10 * time.Minute
+
+// AnnounceStartup sends a standard "service started" message with build info and meta.
+// The announcement is sent once per day for each service version,
+// so a crash-looping service does not spam the channel.
+// If CrashLoopThreshold is set and the service version announced itself more than
+// CrashLoopThreshold times within CrashLoopWindow, a "possible crash loop" alert
+// tagged "crashloop:<service>:<version>" is sent once per hour instead of the announcement,
+// unless it is acknowledged, see Acknowledge.
+func (slacker Slacker) AnnounceStartup(serviceName string, version string, meta map[string]string) error {
+	if err := slacker.setDefaults(); err != nil {
+		return fmt.Errorf("Slacker failed to announce startup: %s", err)
+	}
+
+	// Announcements have their own frequency and are never collected into digests
+	slacker.FrequencyDuration = 0
+	slacker.DigestWindow = 0
+
+	if slacker.CrashLoopThreshold > 0 {
+		starts, err := slacker.recordStartup(serviceName, version, time.Now())
+		if err != nil {
+			slacker.Log.Errorf("Slacker failed to announce startup: %s", err)
+			return err
+		}
+
+		alert := slacker
+		alert.MessageTag = "crashloop:" + serviceName + ":" + version
+		alert.Frequency = NotifyOnceHour
+		alert.Alignment = AlignCalendar
+
+		if starts > slacker.CrashLoopThreshold {
+			if ack := alert.acknowledgement(); ack.by != "" {
+				slacker.Log.Infof("Skip crash loop alert %s acknowledged by %s", alert.MessageTag, ack.by)
+			} else {
+				slacker.emit(EventEscalate, "", serviceName+" "+version, nil)
+				return alert.Send(fmt.Sprintf("Possible crash loop: service %s %s started %d times in %s",
+					serviceName, version, starts, slacker.CrashLoopWindow))
+			}
+		}
+	}
+
+	slacker.MessageTag = "startup:" + serviceName + ":" + version
+	slacker.Frequency = NotifyOnceDay
+	slacker.Alignment = AlignCalendar
+
+	return slacker.Send(startupMessage(serviceName, version, meta))
+}
+
+// recordStartup stores the startup time of the service version and returns
+// the number of startups within CrashLoopWindow including this one
+func (slacker Slacker) recordStartup(serviceName string, version string, now time.Time) (starts int, err error) {
+	err = slacker.updateCounter("starts:"+serviceName+":"+version, func(record *dbRecord) *dbRecord {
+		next := &dbRecord{Message: serviceName + " " + version, State: recordConfirmed, Time: now}
+		if record != nil {
+			for _, start := range record.Occurrences {
+				if now.Sub(start) < slacker.CrashLoopWindow {
+					next.Occurrences = append(next.Occurrences, start)
+				}
+			}
+		}
+		next.Occurrences = append(next.Occurrences, now)
+		starts = len(next.Occurrences)
+		return next
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return starts, nil
+}
+
+func startupMessage(serviceName string, version string, meta map[string]string) string {
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = "unknown host"
+	}
+
+	lines := []string{
+		fmt.Sprintf("Service %s %s started on %s", serviceName, version, hostname),
+		fmt.Sprintf("pid: %d", os.Getpid()),
+		fmt.Sprintf("go: %s %s/%s", runtime.Version(), runtime.GOOS, runtime.GOARCH),
+	}
+
+	if info, ok := debug.ReadBuildInfo(); ok {
+		for _, setting := range info.Settings {
+			if setting.Key == "vcs.revision" || setting.Key == "vcs.time" || setting.Key == "vcs.modified" {
+				lines = append(lines, setting.Key+": "+setting.Value)
+			}
+		}
+	}
+
+	keys := make([]string, 0, len(meta))
+	for key := range meta {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		lines = append(lines, key+": "+meta[key])
+	}
+
+	return strings.Join(lines, "\n")
+}
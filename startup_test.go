@@ -0,0 +1,57 @@
+package slacker
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestAnnounceStartupIgnoresWindowSettings(t *testing.T) {
+	hook := newTestHook(t)
+	slacker := newTestSlacker(t, hook)
+	slacker.FrequencyDuration = time.Second
+	slacker.DigestWindow = time.Hour
+
+	for i := 0; i < 2; i++ {
+		if err := slacker.AnnounceStartup("api", "1.0", nil); err != nil {
+			t.Fatal(err)
+		}
+		time.Sleep(time.Second)
+	}
+
+	if len(hook.posted("Service api 1.0 started")) != 1 {
+		t.Fatalf("Startup is not announced once per day: %v", hook.payloads)
+	}
+}
+
+func TestConcurrentStartupsAreCounted(t *testing.T) {
+	slacker := newTestSlacker(t, newTestHook(t))
+	slacker.CrashLoopThreshold = 100
+	if err := slacker.setDefaults(); err != nil {
+		t.Fatal(err)
+	}
+	if slacker.CrashLoopWindow != DefaultCrashLoopWindow {
+		t.Fatalf("CrashLoopWindow is %s without a default", slacker.CrashLoopWindow)
+	}
+
+	const startups = 20
+	var wait sync.WaitGroup
+	for i := 0; i < startups; i++ {
+		wait.Add(1)
+		go func() {
+			defer wait.Done()
+			if _, err := slacker.recordStartup("api", "1.0", time.Now()); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wait.Wait()
+
+	starts, err := slacker.recordStartup("api", "1.0", time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if starts != startups+1 {
+		t.Fatalf("Counted %d of %d startups", starts, startups+1)
+	}
+}
@@ -0,0 +1,104 @@
+package slacker
+
+import (
+	"fmt"
+	"time"
+)
+
+// StatsBucket holds the number of messages of a tag sent and suppressed within a time bucket
+type StatsBucket struct {
+	Start      time.Time `json:"start"`
+	Sent       int       `json:"sent"`
+	Suppressed int       `json:"suppressed"`
+}
+
+// statsResolution is a bucket length kept for retention, finer buckets are kept for shorter
+type statsResolution struct {
+	name      string
+	length    time.Duration
+	retention time.Duration
+}
+
+var statsResolutions = []statsResolution{
+	{"5m", 5 * time.Minute, 24 * time.Hour},
+	{"1h", time.Hour, 30 * 24 * time.Hour},
+	{"1d", 24 * time.Hour, 400 * 24 * time.Hour},
+}
+
+// statsHash is the database key of the bucket of the tag starting at start. Like calendar windows
+// it embeds when the bucket expires, so buckets older than retention are pruned with them.
+func (slacker Slacker) statsHash(resolution statsResolution, start time.Time) string {
+	return start.Format("2006-01-02T150405Z") + "/" + resolution.retention.String() + ":stats:" + resolution.name + ":" + slacker.MessageTag
+}
+
+// recordStats counts a sent or suppressed message of the tag at now in the bucket of every resolution.
+// Every bucket is a record of its own, so a message rewrites only three small records.
+func (slacker Slacker) recordStats(sent bool, now time.Time) {
+	for _, resolution := range statsResolutions {
+		start := now.UTC().Truncate(resolution.length)
+		err := slacker.updateCounter(slacker.statsHash(resolution, start), func(record *dbRecord) *dbRecord {
+			bucket := StatsBucket{Start: start}
+			if record != nil && record.Bucket != nil {
+				bucket = *record.Bucket
+			}
+
+			if sent {
+				bucket.Sent++
+			} else {
+				bucket.Suppressed++
+			}
+
+			return &dbRecord{State: recordConfirmed, Time: now, Bucket: &bucket}
+		})
+		if err != nil {
+			slacker.Log.Errorf("Slacker failed to record statistics, the message is not counted: %s", err)
+		}
+	}
+}
+
+// Stats returns statistics buckets of MessageTag of resolution, 5 minutes, an hour or a day,
+// starting at or after since. 5 minute buckets are kept for a day, hour buckets for 30 days
+// and day buckets for 400 days. Messages are counted only with Statistics set.
+func (slacker Slacker) Stats(resolution time.Duration, since time.Time) ([]StatsBucket, error) {
+	if err := slacker.setDefaults(); err != nil {
+		return nil, fmt.Errorf("Slacker failed to get statistics: %s", err)
+	}
+
+	var known *statsResolution
+	for i := range statsResolutions {
+		if statsResolutions[i].length == resolution {
+			known = &statsResolutions[i]
+		}
+	}
+	if known == nil {
+		return nil, fmt.Errorf("Slacker failed to get statistics: Unknown resolution %s", resolution)
+	}
+
+	now := time.Now().UTC()
+	first := now.Add(-known.retention).Truncate(resolution)
+	if since.After(first) {
+		first = since.UTC().Truncate(resolution)
+		if first.Before(since) {
+			first = first.Add(resolution)
+		}
+	}
+
+	var hashes []string
+	for start := first; !start.After(now); start = start.Add(resolution) {
+		hashes = append(hashes, slacker.statsHash(*known, start))
+	}
+
+	db, err := slacker.getRecords(hashes)
+	if err != nil {
+		return nil, err
+	}
+
+	var buckets []StatsBucket
+	for _, hash := range hashes {
+		if record, ok := db[hash]; ok && record.Bucket != nil {
+			buckets = append(buckets, *record.Bucket)
+		}
+	}
+
+	return buckets, nil
+}
@@ -0,0 +1,85 @@
+package slacker
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestStatsCountConcurrentSends(t *testing.T) {
+	hook := newTestHook(t)
+	slacker := newTestSlacker(t, hook)
+	slacker.Statistics = true
+
+	const senders = 30
+	var wait sync.WaitGroup
+	for i := 0; i < senders; i++ {
+		wait.Add(1)
+		go func() {
+			defer wait.Done()
+			slacker.Send("message")
+		}()
+	}
+	wait.Wait()
+
+	buckets, err := slacker.Stats(24*time.Hour, time.Now().Add(-48*time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var sent, suppressed int
+	for _, bucket := range buckets {
+		sent += bucket.Sent
+		suppressed += bucket.Suppressed
+	}
+	if sent != 1 || suppressed != senders-1 {
+		t.Fatalf("Counted %d sent and %d suppressed messages of %d", sent, suppressed, senders)
+	}
+}
+
+func TestStatsWriteOnlyCurrentBuckets(t *testing.T) {
+	hook := newTestHook(t)
+	slacker := newTestSlacker(t, hook)
+	slacker.Statistics = true
+	slacker.Frequency = NotifyAlways
+
+	now := time.Now()
+	for i := 1; i <= 200; i++ {
+		slacker.recordStats(true, now.Add(-time.Duration(i)*5*time.Minute))
+	}
+
+	before := slacker.dbSize()
+	if err := slacker.Send("message"); err != nil {
+		t.Fatal(err)
+	}
+	if grown := slacker.dbSize() - before; grown > 4096 {
+		t.Fatalf("A message grows the database by %d bytes", grown)
+	}
+
+	buckets, err := slacker.Stats(5*time.Minute, now.Add(-24*time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(buckets) != 201 {
+		t.Fatalf("Got %d of 201 buckets", len(buckets))
+	}
+}
+
+func TestStatsBucketsExpire(t *testing.T) {
+	hook := newTestHook(t)
+	slacker := newTestSlacker(t, hook)
+	slacker.Statistics = true
+
+	slacker.recordStats(true, time.Now().Add(-48*time.Hour))
+	if _, err := slacker.PruneExpired(); err != nil {
+		t.Fatal(err)
+	}
+
+	db, err := slacker.loadDb()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(db) != 2 {
+		t.Fatalf("Kept %d records, the expired 5 minute bucket is not pruned: %v", len(db), db)
+	}
+}
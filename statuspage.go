@@ -0,0 +1,147 @@
+package slacker
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+const (
+	statuspageApiUrl        = "https://api.statuspage.io/v1/pages/"
+	DefaultStatuspageStatus = "investigating"
+
+	statuspageResolved = "resolved"
+)
+
+// StatuspageConfig holds Statuspage.io settings for incidents mirrored from Slack notifications
+type StatuspageConfig struct {
+	ApiKey string
+	PageId string
+	Tags   []string // MessageTags mirrored to Statuspage
+	Status string   // Optional, incident status, DefaultStatuspageStatus by default
+}
+
+type statuspageIncident struct {
+	Id     string `json:"id,omitempty"`
+	Name   string `json:"name,omitempty"`
+	Status string `json:"status,omitempty"`
+	Body   string `json:"body,omitempty"`
+}
+
+func (config StatuspageConfig) isMirrored(tag string) bool {
+	for _, mirrored := range config.Tags {
+		if mirrored == tag {
+			return true
+		}
+	}
+
+	return false
+}
+
+// updateStatuspage creates an incident named after MessageTag on the first message,
+// adds later messages of the tag as updates to the same incident and resolves it on Resolve
+func (slacker *Slacker) updateStatuspage(message string) {
+	if slacker.Statuspage == nil || !slacker.Statuspage.isMirrored(slacker.MessageTag) {
+		return
+	}
+
+	hash := slacker.statuspageHash()
+	record, found, err := slacker.getRecord(hash)
+	if err != nil {
+		slacker.Log.Errorf("Slacker failed to update Statuspage: %s", err)
+		return
+	}
+
+	open := found && record.IncidentId != ""
+	if !open && slacker.resolving {
+		return
+	}
+
+	status := slacker.Statuspage.Status
+	if status == "" {
+		status = DefaultStatuspageStatus
+	}
+	if slacker.resolving {
+		status = statuspageResolved
+	}
+
+	incident := statuspageIncident{Status: status, Body: message}
+	if open {
+		incident.Id = record.IncidentId
+	} else {
+		incident.Name = slacker.MessageTag
+	}
+
+	incident, err = slacker.saveIncident(incident)
+	if err != nil {
+		slacker.Log.Errorf("Slacker failed to update Statuspage: %s", err)
+		return
+	}
+
+	next := &dbRecord{Message: message, State: recordConfirmed, Time: time.Now(), IncidentId: incident.Id}
+	if found {
+		next.Version = record.Version
+	}
+
+	switch {
+	case slacker.resolving:
+		slacker.Log.Infof("Resolve Statuspage incident %s: %s", incident.Id, message)
+		next.IncidentId = ""
+	case open:
+		slacker.Log.Infof("Update Statuspage incident %s: %s", incident.Id, message)
+		return
+	default:
+		slacker.Log.Infof("Create Statuspage incident %s: %s", incident.Id, message)
+	}
+
+	err = slacker.putRecord(hash, next)
+	if err == errConflict {
+		slacker.Log.Errorf("Slacker failed to update Statuspage: incident of %s was changed by another process meanwhile", slacker.MessageTag)
+		return
+	}
+	if err != nil {
+		slacker.Log.Errorf("Slacker failed to update Statuspage: %s", err)
+	}
+}
+
+// statuspageHash is the database key of the open incident of the tag
+func (slacker Slacker) statuspageHash() string {
+	return "statuspage:" + slacker.MessageTag
+}
+
+// saveIncident creates incident without Id or updates the existing one
+func (slacker *Slacker) saveIncident(incident statuspageIncident) (statuspageIncident, error) {
+	method := http.MethodPost
+	apiUrl := statuspageApiUrl + url.PathEscape(slacker.Statuspage.PageId) + "/incidents"
+	if incident.Id != "" {
+		method = http.MethodPatch
+		apiUrl += "/" + url.PathEscape(incident.Id)
+	}
+
+	payload, err := json.Marshal(map[string]statuspageIncident{"incident": incident})
+	if err != nil {
+		return incident, err
+	}
+
+	request, err := http.NewRequest(method, apiUrl, bytes.NewReader(payload))
+	if err != nil {
+		return incident, err
+	}
+	request.Header.Set("Authorization", "OAuth "+slacker.Statuspage.ApiKey)
+	request.Header.Set("Content-Type", "application/json")
+
+	body, err := slacker.doRequest("Statuspage", request)
+	if err != nil {
+		return incident, err
+	}
+
+	var saved statuspageIncident
+	if err := json.Unmarshal(body, &saved); err != nil {
+		return incident, fmt.Errorf("Failed to decode Statuspage incident: %s", err)
+	}
+
+	return saved, nil
+}
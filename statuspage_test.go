@@ -0,0 +1,62 @@
+package slacker
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// statuspageTransport answers Slack posts and records Statuspage requests
+type statuspageTransport struct {
+	mutex    sync.Mutex
+	requests []string // Method, path and incident status
+}
+
+func (transport *statuspageTransport) RoundTrip(request *http.Request) (*http.Response, error) {
+	body := "ok"
+	if request.URL.Host == "api.statuspage.io" {
+		var payload map[string]statuspageIncident
+		data, _ := ioutil.ReadAll(request.Body)
+		json.Unmarshal(data, &payload)
+
+		transport.mutex.Lock()
+		transport.requests = append(transport.requests, request.Method+" "+request.URL.Path+" "+payload["incident"].Status)
+		transport.mutex.Unlock()
+		body = `{"id": "incident1"}`
+	}
+
+	return &http.Response{StatusCode: http.StatusOK, Body: ioutil.NopCloser(strings.NewReader(body)), Request: request}, nil
+}
+
+func TestStatuspageIncidentIsResolved(t *testing.T) {
+	transport := &statuspageTransport{}
+	slacker := newTestSlacker(t, newTestHook(t))
+	slacker.Hook = "https://hooks.slack.com/services/test"
+	slacker.Frequency = NotifyAlways
+	slacker.HttpClient = &http.Client{Transport: transport}
+	slacker.Statuspage = &StatuspageConfig{ApiKey: "key", PageId: "page", Tags: []string{"test"}}
+
+	for _, send := range []func() error{
+		func() error { return slacker.Send("down") },
+		func() error { return slacker.Send("still down") },
+		func() error { return slacker.Resolve("up") },
+		func() error { return slacker.Send("down again") },
+	} {
+		if err := send(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	expected := []string{
+		"POST /v1/pages/page/incidents investigating",
+		"PATCH /v1/pages/page/incidents/incident1 investigating",
+		"PATCH /v1/pages/page/incidents/incident1 resolved",
+		"POST /v1/pages/page/incidents investigating",
+	}
+	if strings.Join(transport.requests, "\n") != strings.Join(expected, "\n") {
+		t.Fatalf("Unexpected Statuspage requests:\n%s", strings.Join(transport.requests, "\n"))
+	}
+}
@@ -0,0 +1,133 @@
+package slacker
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Store keeps records of Slacker instead of the database file, e.g. MemoryStore.
+// Record values are opaque to stores, a store only needs versioned writes,
+// so concurrent Slackers sharing the store send every message once.
+type Store interface {
+	// Get returns the record of key, found is false if there is none or it expired
+	Get(key string) (record StoreRecord, found bool, err error)
+
+	// Put writes records whose stored version still equals their Version, 0 for a new record,
+	// or whatever version they have if Force, and increments the stored version.
+	// Records changed by another writer since they were read are not written and returned as conflicts.
+	Put(records []StoreRecord) (conflicts map[string]bool, err error)
+}
+
+// StoreRecord is a record kept by Store
+type StoreRecord struct {
+	Key     string
+	Value   []byte
+	Version int
+	Force   bool
+	Expires time.Time // The record may be removed after it, zero if it never expires
+}
+
+// getStoreRecords returns records of hashes found in Store
+func (slacker Slacker) getStoreRecords(hashes []string) (db map[string]dbRecord, err error) {
+	if err := slacker.context().Err(); err != nil {
+		return nil, err
+	}
+
+	db = make(map[string]dbRecord, len(hashes))
+	for _, hash := range hashes {
+		stored, found, err := slacker.Store.Get(hash)
+		if err != nil {
+			return nil, fmt.Errorf("Slacker failed to load %s from store: %s", hash, err)
+		}
+		if !found {
+			continue
+		}
+
+		record, err := slacker.decodeRecord(stored.Value)
+		if err != nil {
+			slacker.Log.Errorf("Skip store record %s failed to decode: %s", hash, err)
+			continue
+		}
+		record.Version = stored.Version
+		db[hash] = record
+	}
+
+	return db, nil
+}
+
+// putStoreRecords writes records to Store like putRecordsFile does to the database file
+func (slacker Slacker) putStoreRecords(records map[string]*dbRecord, force bool) (conflicts map[string]bool, err error) {
+	if err := slacker.context().Err(); err != nil {
+		return nil, err
+	}
+
+	if slacker.ReadOnly {
+		slacker.Log.Debugf("Skip store write of %d records in read-only mode", len(records))
+		slacker.emit(EventSkipWrite, "", "", nil)
+		return nil, nil
+	}
+
+	batch := make([]StoreRecord, 0, len(records))
+	for hash, record := range records {
+		value, err := slacker.encodeRecord(*record)
+		if err != nil {
+			return nil, err
+		}
+
+		batch = append(batch, StoreRecord{
+			Key:     hash,
+			Value:   value,
+			Version: record.Version,
+			Force:   force,
+			Expires: slacker.recordExpiry(hash, *record),
+		})
+	}
+
+	conflicts, err = slacker.Store.Put(batch)
+	if err != nil {
+		return nil, fmt.Errorf("Slacker failed to write to store: %s", err)
+	}
+
+	for hash, record := range records {
+		if !conflicts[hash] {
+			record.Version++
+		}
+	}
+
+	return conflicts, nil
+}
+
+func (slacker Slacker) encodeRecord(record dbRecord) ([]byte, error) {
+	value, err := json.Marshal(record)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to encode record: %s", err)
+	}
+
+	return encodeLine(slacker.Codec, value)
+}
+
+func (slacker Slacker) decodeRecord(value []byte) (record dbRecord, err error) {
+	value, err = decodeLine(slacker.Codec, value)
+	if err != nil {
+		return record, err
+	}
+
+	err = json.Unmarshal(value, &record)
+	return record, err
+}
+
+// recordExpiry returns when the record of hash is not needed anymore: the end of its calendar window,
+// or of the rolling window since it was sent. Other records never expire.
+func (slacker Slacker) recordExpiry(hash string, record dbRecord) time.Time {
+	if windowEnd, ok := hashWindowEnd(hash); ok {
+		return windowEnd
+	}
+
+	if strings.HasPrefix(hash, "rolling:") {
+		return record.Time.Add(slacker.window())
+	}
+
+	return time.Time{}
+}
@@ -0,0 +1,69 @@
+package slacker
+
+import (
+	"fmt"
+	"time"
+)
+
+// suppressionHash is the database key of messages suppressed since the last sent message of the tag.
+// The record outlives dedup windows, so the first message of the next window can report them.
+func (slacker Slacker) suppressionHash() string {
+	return "suppressed:" + slacker.MessageTag
+}
+
+// recordSuppressed counts message as suppressed at now and keeps the first and the last suppressed bodies
+func (slacker Slacker) recordSuppressed(message string, now time.Time) {
+	err := slacker.updateCounter(slacker.suppressionHash(), func(record *dbRecord) *dbRecord {
+		next := &dbRecord{State: recordConfirmed, Time: now, FirstSuppressed: message}
+		if record != nil && record.Suppressed > 0 {
+			next = record
+		}
+		next.Suppressed++
+		next.LastSuppressed = message
+		return next
+	})
+	if err != nil {
+		slacker.Log.Errorf("Slacker failed to count suppressed message, it is not counted: %s", err)
+	}
+}
+
+// takeSuppressionNotice resets the count of suppressed messages
+// and returns a notice about them to prepend to the sent message
+func (slacker Slacker) takeSuppressionNotice() string {
+	hash := slacker.suppressionHash()
+	for attempt := 0; attempt < maxUpdateAttempts; attempt++ {
+		record, found, err := slacker.getRecord(hash)
+		if err != nil {
+			slacker.Log.Errorf("Slacker failed to get suppressed messages: %s", err)
+			return ""
+		}
+		if !found || record.Suppressed == 0 {
+			return ""
+		}
+
+		notice := suppressionNotice(*record)
+
+		err = slacker.putRecord(hash, &dbRecord{State: recordConfirmed, Time: time.Now(), Version: record.Version})
+		if err == errConflict {
+			continue
+		}
+		if err != nil {
+			slacker.Log.Errorf("Slacker failed to reset suppressed messages: %s", err)
+			return ""
+		}
+
+		return notice
+	}
+
+	return ""
+}
+
+func suppressionNotice(record dbRecord) string {
+	if record.Suppressed == 1 {
+		return fmt.Sprintf("suppressed 1 similar message since %s (shown below)\n> %s\n\n",
+			record.Time.Format("15:04"), record.FirstSuppressed)
+	}
+
+	return fmt.Sprintf("suppressed %d similar messages since %s (first/last shown below)\n> %s\n> %s\n\n",
+		record.Suppressed, record.Time.Format("15:04"), record.FirstSuppressed, record.LastSuppressed)
+}
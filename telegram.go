@@ -0,0 +1,56 @@
+package slacker
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+)
+
+const telegramApiUrl = "https://api.telegram.org/bot"
+
+// TelegramSink sends messages to a Telegram chat with a bot token
+type TelegramSink struct {
+	BotToken   string
+	ChatId     string
+	HttpClient *http.Client // Optional
+}
+
+type telegramMessage struct {
+	ChatId string `json:"chat_id"`
+	Text   string `json:"text"`
+}
+
+// Payload implements PayloadRenderer
+func (sink TelegramSink) Payload(message Message) ([]byte, error) {
+	return json.Marshal(telegramMessage{ChatId: sink.ChatId, Text: message.Text})
+}
+
+// Notify implements Notifier
+func (sink TelegramSink) Notify(message Message) error {
+	return sink.NotifyContext(context.Background(), message)
+}
+
+// NotifyContext implements ContextNotifier
+func (sink TelegramSink) NotifyContext(ctx context.Context, message Message) error {
+	payload, err := sink.Payload(message)
+	if err != nil {
+		return err
+	}
+
+	request, err := http.NewRequest(http.MethodPost, telegramApiUrl+sink.BotToken+"/sendMessage", bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Content-Type", "application/json")
+
+	_, err = doSinkRequest(ctx, sink.HttpClient, "Telegram", request)
+	return err
+}
+
+// Lint implements Linter
+func (sink TelegramSink) Lint(message Message) error {
+	l := &lint{service: "Telegram"}
+	l.length("text", message.Text, 4096)
+	return l.err()
+}
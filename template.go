@@ -0,0 +1,76 @@
+package slacker
+
+import (
+	"bytes"
+	"fmt"
+	"sync"
+	"text/template"
+)
+
+// templates holds message templates registered with RegisterTemplate
+var templates = struct {
+	sync.RWMutex
+	byName map[string]*template.Template
+}{byName: make(map[string]*template.Template)}
+
+// templateFuncs are available in message templates, e.g. {{formatDate .Time "{date_short} {time}"}},
+// {{formatNumber .Count 0 "en"}} or {{json .Labels}}
+var templateFuncs = template.FuncMap{
+	"formatDate":   FormatDate,
+	"formatNumber": FormatNumber,
+	"json":         toJson,
+}
+
+// RegisterTemplate parses tmpl as a text/template and registers it under name for SendTemplate,
+// replacing a template registered before under the same name
+func RegisterTemplate(name string, tmpl string) error {
+	compiled, err := compileTemplate(name, "message", templateFuncs, tmpl)
+	if err != nil {
+		return fmt.Errorf("Failed to parse template %s: %s", name, err)
+	}
+
+	templates.Lock()
+	templates.byName[name] = compiled
+	templates.Unlock()
+
+	return nil
+}
+
+// SendTemplate renders the template registered under name with data and sends it like Send
+// In Strict mode a template not registered panics.
+func (slacker Slacker) SendTemplate(name string, data interface{}) error {
+	if slacker.Strict && !isTemplateRegistered(name) {
+		panic(fmt.Sprintf("Slacker is misconfigured: Template %s is not registered", name))
+	}
+
+	message, err := renderTemplate(name, data)
+	if err != nil {
+		return fmt.Errorf("Slacker failed to send message: %s", err)
+	}
+
+	return slacker.Send(message)
+}
+
+func isTemplateRegistered(name string) bool {
+	templates.RLock()
+	defer templates.RUnlock()
+
+	_, ok := templates.byName[name]
+	return ok
+}
+
+func renderTemplate(name string, data interface{}) (string, error) {
+	templates.RLock()
+	tmpl, ok := templates.byName[name]
+	templates.RUnlock()
+	if !ok {
+		return "", fmt.Errorf("Template %s is not registered", name)
+	}
+
+	var message bytes.Buffer
+	if err := tmpl.Execute(&message, data); err != nil {
+		return "", fmt.Errorf("Failed to execute template %s: %s", name, err)
+	}
+
+	return message.String(), nil
+}
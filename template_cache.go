@@ -0,0 +1,61 @@
+package slacker
+
+import (
+	"sync"
+	"sync/atomic"
+	"text/template"
+)
+
+// templateCache holds compiled templates by their name, functions set and text,
+// so templates used for every message are parsed once per process
+var templateCache = struct {
+	sync.RWMutex
+	templates map[string]*template.Template
+	hits      int64
+	misses    int64
+}{templates: make(map[string]*template.Template)}
+
+// TemplateCacheStats holds counters of the compiled template cache
+type TemplateCacheStats struct {
+	Templates int   // Number of cached templates
+	Hits      int64 // Number of lookups which found a compiled template
+	Misses    int64 // Number of lookups which parsed the template
+}
+
+// GetTemplateCacheStats returns counters of the compiled template cache
+func GetTemplateCacheStats() TemplateCacheStats {
+	templateCache.RLock()
+	defer templateCache.RUnlock()
+
+	return TemplateCacheStats{
+		Templates: len(templateCache.templates),
+		Hits:      atomic.LoadInt64(&templateCache.hits),
+		Misses:    atomic.LoadInt64(&templateCache.misses),
+	}
+}
+
+// compileTemplate returns text parsed as template named name with funcs,
+// funcsName tells function sets apart in the cache
+func compileTemplate(name string, funcsName string, funcs template.FuncMap, text string) (*template.Template, error) {
+	key := name + "\x00" + funcsName + "\x00" + text
+
+	templateCache.RLock()
+	tmpl, ok := templateCache.templates[key]
+	templateCache.RUnlock()
+	if ok {
+		atomic.AddInt64(&templateCache.hits, 1)
+		return tmpl, nil
+	}
+
+	atomic.AddInt64(&templateCache.misses, 1)
+	tmpl, err := template.New(name).Funcs(funcs).Parse(text)
+	if err != nil {
+		return nil, err
+	}
+
+	templateCache.Lock()
+	templateCache.templates[key] = tmpl
+	templateCache.Unlock()
+
+	return tmpl, nil
+}
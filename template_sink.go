@@ -0,0 +1,66 @@
+package slacker
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"text/template"
+)
+
+// TemplateSink posts messages to any webhook accepting JSON, e.g. Zulip or Google Chat,
+// with the body rendered by a text/template over Message.
+// The "json" template function encodes a value as JSON, e.g. {"text": {{json .Text}}}.
+type TemplateSink struct {
+	Url        string
+	Template   string
+	Header     map[string]string // Optional
+	HttpClient *http.Client      // Optional
+}
+
+// Payload implements PayloadRenderer
+func (sink TemplateSink) Payload(message Message) ([]byte, error) {
+	tmpl, err := compileTemplate("payload", "sink", template.FuncMap{"json": toJson}, sink.Template)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to parse payload template: %s", err)
+	}
+
+	var body bytes.Buffer
+	if err := tmpl.Execute(&body, message); err != nil {
+		return nil, fmt.Errorf("Failed to execute payload template: %s", err)
+	}
+
+	return body.Bytes(), nil
+}
+
+// Notify implements Notifier
+func (sink TemplateSink) Notify(message Message) error {
+	return sink.NotifyContext(context.Background(), message)
+}
+
+// NotifyContext implements ContextNotifier
+func (sink TemplateSink) NotifyContext(ctx context.Context, message Message) error {
+	payload, err := sink.Payload(message)
+	if err != nil {
+		return err
+	}
+
+	request, err := http.NewRequest(http.MethodPost, sink.Url, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+
+	request.Header.Set("Content-Type", "application/json")
+	for name, value := range sink.Header {
+		request.Header.Set(name, value)
+	}
+
+	_, err = doSinkRequest(ctx, sink.HttpClient, sink.Url, request)
+	return err
+}
+
+func toJson(value interface{}) (string, error) {
+	encoded, err := json.Marshal(value)
+	return string(encoded), err
+}
@@ -0,0 +1,65 @@
+package slacker
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// threadHash is the database key of the thread of the tag messages to recipient
+func (slacker Slacker) threadHash(recipient Recipient) string {
+	return "thread:" + slacker.MessageTag + ":" + recipient.id()
+}
+
+func (slacker Slacker) threads() bool {
+	return slacker.ThreadReplies && slacker.BotToken != ""
+}
+
+// threadTs returns the ts of the first message of the tag to recipient,
+// or an empty string if there is no thread to reply in
+func (slacker Slacker) threadTs(recipient Recipient) string {
+	if !slacker.threads() {
+		return ""
+	}
+
+	record, found, err := slacker.getRecord(slacker.threadHash(recipient))
+	if err != nil {
+		slacker.Log.Errorf("Slacker failed to get message thread: %s", err)
+		return ""
+	}
+	if !found {
+		return ""
+	}
+
+	return record.ThreadTs
+}
+
+// startThread records the ts of a Web API response, so next messages of the tag to recipient are replies to it
+func (slacker Slacker) startThread(recipient Recipient, response string) {
+	if !slacker.threads() {
+		return
+	}
+
+	var posted apiResponse
+	if err := json.Unmarshal([]byte(response), &posted); err != nil || posted.Ts == "" {
+		return
+	}
+
+	record := dbRecord{State: recordConfirmed, Time: time.Now(), ThreadTs: posted.Ts}
+	if err := slacker.setRecord(slacker.threadHash(recipient), record); err != nil {
+		slacker.Log.Errorf("Slacker failed to record message thread: %s", err)
+	}
+}
+
+// closeThreads lets the next message of the tag start new threads
+func (slacker Slacker) closeThreads() {
+	if !slacker.threads() {
+		return
+	}
+
+	for _, recipient := range slacker.To {
+		record := dbRecord{State: recordConfirmed, Time: time.Now()}
+		if err := slacker.setRecord(slacker.threadHash(recipient), record); err != nil {
+			slacker.Log.Errorf("Slacker failed to close message thread: %s", err)
+		}
+	}
+}
@@ -0,0 +1,127 @@
+package slacker
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	DefaultSmsInterval time.Duration = time.Hour
+
+	twilioApiUrl        = "https://api.twilio.com/2010-04-01/Accounts/"
+	twilioMaxBodyLength = 1600
+)
+
+// TwilioConfig holds Twilio SMS settings used when Slack delivery fails
+type TwilioConfig struct {
+	AccountSid string
+	AuthToken  string
+	From       string   // Twilio phone number
+	To         []string // Phone numbers to notify
+	Tags       []string // MessageTags treated as critical, no message is sent as SMS if empty
+
+	// Interval is optional, SMS of a tag are sent at most once per Interval
+	// while Slack keeps failing, DefaultSmsInterval by default
+	Interval time.Duration
+}
+
+func (config TwilioConfig) isCritical(tag string) bool {
+	for _, critical := range config.Tags {
+		if critical == tag {
+			return true
+		}
+	}
+
+	return false
+}
+
+// sendSmsFallback sends critical message as SMS via Twilio after Slack delivery failed
+func (slacker *Slacker) sendSmsFallback(message string) {
+	if slacker.Twilio == nil || !slacker.Twilio.isCritical(slacker.MessageTag) {
+		return
+	}
+
+	if ack := slacker.acknowledgement(); ack.by != "" {
+		slacker.Log.Infof("Skip SMS fallback %s acknowledged by %s", slacker.MessageTag, ack.by)
+		return
+	}
+
+	if !slacker.claimSms(time.Now()) {
+		slacker.Log.Infof("Skip SMS fallback %s already sent within %s", slacker.MessageTag, slacker.smsInterval())
+		return
+	}
+
+	body := truncate(slacker.MessageTag+": "+message, twilioMaxBodyLength)
+	for _, to := range slacker.Twilio.To {
+		if err := slacker.sendSms(to, body); err != nil {
+			slacker.Log.Errorf("Slacker failed to send SMS fallback to %s: %s", to, err)
+			continue
+		}
+
+		slacker.Log.Infof("Send SMS fallback %s to %s", slacker.MessageTag, to)
+	}
+}
+
+// smsHash is the database key of the last SMS fallback of the tag
+func (slacker Slacker) smsHash() string {
+	return "sms:" + slacker.MessageTag
+}
+
+func (slacker Slacker) smsInterval() time.Duration {
+	if slacker.Twilio.Interval > 0 {
+		return slacker.Twilio.Interval
+	}
+
+	return DefaultSmsInterval
+}
+
+// claimSms records the SMS fallback of the tag at now and returns false
+// if this or another process already sent one within Interval.
+// SMS are sent if the database fails, Slack may be failing for the same reason.
+func (slacker Slacker) claimSms(now time.Time) bool {
+	hash := slacker.smsHash()
+	record, found, err := slacker.getRecord(hash)
+	if err != nil {
+		slacker.Log.Errorf("Slacker failed to get last SMS fallback: %s", err)
+		return true
+	}
+
+	next := &dbRecord{State: recordConfirmed, Time: now}
+	if found {
+		if now.Before(record.Time.Add(slacker.smsInterval())) {
+			return false
+		}
+		next.Version = record.Version
+	}
+
+	err = slacker.putRecord(hash, next)
+	if err == errConflict {
+		return false
+	}
+	if err != nil {
+		slacker.Log.Errorf("Slacker failed to record SMS fallback: %s", err)
+	}
+
+	return true
+}
+
+func (slacker *Slacker) sendSms(to string, body string) error {
+	apiUrl := twilioApiUrl + url.PathEscape(slacker.Twilio.AccountSid) + "/Messages.json"
+
+	form := url.Values{}
+	form.Set("To", to)
+	form.Set("From", slacker.Twilio.From)
+	form.Set("Body", body)
+
+	request, err := http.NewRequest(http.MethodPost, apiUrl, strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	request.SetBasicAuth(slacker.Twilio.AccountSid, slacker.Twilio.AuthToken)
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	_, err = slacker.doRequest("Twilio", request)
+	return err
+}
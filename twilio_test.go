@@ -0,0 +1,82 @@
+package slacker
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"unicode/utf8"
+)
+
+// smsTransport fails Slack posts and records SMS bodies sent to Twilio
+type smsTransport struct {
+	mutex  sync.Mutex
+	bodies []string
+}
+
+func (transport *smsTransport) RoundTrip(request *http.Request) (*http.Response, error) {
+	status := http.StatusInternalServerError
+	if request.URL.Host == "api.twilio.com" {
+		body, _ := ioutil.ReadAll(request.Body)
+		form, _ := url.ParseQuery(string(body))
+
+		transport.mutex.Lock()
+		transport.bodies = append(transport.bodies, form.Get("Body"))
+		transport.mutex.Unlock()
+		status = http.StatusCreated
+	}
+
+	return &http.Response{
+		StatusCode: status,
+		Body:       ioutil.NopCloser(strings.NewReader("{}")),
+		Request:    request,
+	}, nil
+}
+
+func newSmsSlacker(t *testing.T, transport *smsTransport, tags []string) Slacker {
+	slacker := newTestSlacker(t, newTestHook(t))
+	slacker.Hook = "https://hooks.slack.com/services/test"
+	slacker.Frequency = NotifyAlways
+	slacker.HttpClient = &http.Client{Transport: transport}
+	slacker.Twilio = &TwilioConfig{AccountSid: "sid", AuthToken: "token", From: "+1", To: []string{"+2"}, Tags: tags}
+	return slacker
+}
+
+func TestSmsFallbackOncePerInterval(t *testing.T) {
+	transport := &smsTransport{}
+	slacker := newSmsSlacker(t, transport, []string{"test"})
+
+	for i := 0; i < 3; i++ {
+		if err := slacker.Send("down"); err == nil {
+			t.Fatal("Failed post returns no error")
+		}
+	}
+
+	if len(transport.bodies) != 1 {
+		t.Fatalf("SMS fallback is sent %d times within Interval", len(transport.bodies))
+	}
+}
+
+func TestSmsFallbackWithoutTags(t *testing.T) {
+	transport := &smsTransport{}
+	slacker := newSmsSlacker(t, transport, nil)
+
+	slacker.Send("down")
+
+	if len(transport.bodies) != 0 {
+		t.Fatal("SMS fallback is sent without critical tags")
+	}
+}
+
+func TestSmsBodyIsCutOnRuneBoundary(t *testing.T) {
+	transport := &smsTransport{}
+	slacker := newSmsSlacker(t, transport, []string{"test"})
+
+	slacker.Send("x" + strings.Repeat("é", twilioMaxBodyLength))
+
+	if len(transport.bodies) != 1 || !utf8.ValidString(transport.bodies[0]) {
+		t.Fatalf("SMS body is not valid UTF-8: %q", transport.bodies)
+	}
+}
@@ -0,0 +1,41 @@
+package slacker
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"math/big"
+	"time"
+)
+
+// crockfordAlphabet is the base32 alphabet of ULIDs
+const crockfordAlphabet = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+// NewUlid returns a ULID: a 26 character, lexicographically sortable identifier
+// made of a millisecond timestamp and 80 random bits
+func NewUlid() string {
+	id := make([]byte, 16)
+	binary.BigEndian.PutUint64(id[:8], uint64(time.Now().UnixNano()/int64(time.Millisecond))<<16)
+	if _, err := rand.Read(id[6:]); err != nil {
+		panic("slacker: failed to read random bytes for ULID: " + err.Error())
+	}
+
+	n := new(big.Int).SetBytes(id)
+	base := big.NewInt(32)
+	digit := new(big.Int)
+
+	encoded := make([]byte, 26)
+	for i := len(encoded) - 1; i >= 0; i-- {
+		n.DivMod(n, base, digit)
+		encoded[i] = crockfordAlphabet[digit.Int64()]
+	}
+
+	return string(encoded)
+}
+
+func (slacker Slacker) newMessageId() string {
+	if slacker.IdGenerator != nil {
+		return slacker.IdGenerator()
+	}
+
+	return NewUlid()
+}
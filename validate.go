@@ -0,0 +1,83 @@
+package slacker
+
+import (
+	"fmt"
+	"net/url"
+	"path"
+	"time"
+)
+
+// Validate checks settings of slacker, e.g. at startup, including the ones
+// Send falls back to defaults for. In Strict mode it panics instead of returning an error.
+func (slacker Slacker) Validate() (err error) {
+	if err := slacker.setDefaults(); err != nil {
+		return err
+	}
+
+	defer func() {
+		if err != nil && slacker.Strict {
+			panic(fmt.Sprintf("Slacker is misconfigured: %s", err))
+		}
+	}()
+
+	if slacker.Frequency < NotifyAlways || slacker.Frequency > NotifyOnceDay {
+		return fmt.Errorf("Unknown frequency %d", slacker.Frequency)
+	}
+
+	for level, config := range slacker.Levels {
+		if config.FrequencyDuration > 0 && config.FrequencyDuration < time.Second {
+			return fmt.Errorf("Frequency duration %s of level %s is shorter than a second", config.FrequencyDuration, level)
+		}
+	}
+
+	if slacker.Alignment != AlignCalendar && slacker.Alignment != AlignRolling {
+		return fmt.Errorf("Unknown alignment %d", slacker.Alignment)
+	}
+
+	if slacker.OversizeAction < OversizeTruncate || slacker.OversizeAction > OversizeReject {
+		return fmt.Errorf("Unknown oversize action %d", slacker.OversizeAction)
+	}
+
+	if slacker.IPVersion != IPAny && slacker.IPVersion != IPv4 && slacker.IPVersion != IPv6 {
+		return fmt.Errorf("Unknown IP version %d", slacker.IPVersion)
+	}
+
+	if err := validateIconUrl(slacker.IconUrl); err != nil {
+		return err
+	}
+
+	for _, owner := range slacker.Owners {
+		for _, pattern := range owner.Tags {
+			if _, err := path.Match(pattern, ""); err != nil {
+				return fmt.Errorf("Owner %s has invalid tag pattern %q: %s", owner.Name, pattern, err)
+			}
+		}
+	}
+
+	for _, recipient := range slacker.To {
+		if err := validateIconUrl(recipient.IconUrl); err != nil {
+			return fmt.Errorf("Recipient %s: %s", recipient.id(), err)
+		}
+
+		// Webhooks post to their own channel by default, the Web API needs one
+		if recipient.Channel == "" && slacker.BotToken != "" {
+			return fmt.Errorf("Recipient %q has no channel, which Web API requires", recipient.Username)
+		}
+	}
+
+	return nil
+}
+
+// validateIconUrl checks that iconUrl is empty or an absolute http or https url
+func validateIconUrl(iconUrl string) error {
+	if iconUrl == "" {
+		return nil
+	}
+
+	parsed, err := url.Parse(iconUrl)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return fmt.Errorf("Icon url %q is not an absolute http or https url", iconUrl)
+	}
+
+	return nil
+}
@@ -0,0 +1,14 @@
+package slacker
+
+import "testing"
+
+func TestValidateRecipientWithoutChannel(t *testing.T) {
+	if _, err := New("https://hooks.slack.com/services/A/B/C", WithRecipients(Recipient{})); err != nil {
+		t.Fatalf("Webhook recipient without channel is rejected: %s", err)
+	}
+
+	slacker := Slacker{BotToken: "xoxb-token", To: []Recipient{{Username: "@alice"}}}
+	if err := slacker.Validate(); err == nil {
+		t.Fatal("Web API recipient without channel is accepted")
+	}
+}
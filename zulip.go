@@ -0,0 +1,71 @@
+package slacker
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// ZulipSink posts messages to a Zulip stream topic with a bot account
+type ZulipSink struct {
+	Site       string // e.g. https://example.zulipchat.com
+	Email      string // Bot email
+	ApiKey     string // Bot API key
+	Stream     string
+	Topic      string       // Optional, the message tag by default
+	HttpClient *http.Client // Optional
+}
+
+// Payload implements PayloadRenderer, the form is encoded with keys sorted
+func (sink ZulipSink) Payload(message Message) ([]byte, error) {
+	topic := sink.Topic
+	if topic == "" {
+		topic = message.MessageTag
+	}
+
+	form := url.Values{}
+	form.Set("type", "stream")
+	form.Set("to", sink.Stream)
+	form.Set("topic", topic)
+	form.Set("content", message.Text)
+
+	return []byte(form.Encode()), nil
+}
+
+// Notify implements Notifier
+func (sink ZulipSink) Notify(message Message) error {
+	return sink.NotifyContext(context.Background(), message)
+}
+
+// NotifyContext implements ContextNotifier
+func (sink ZulipSink) NotifyContext(ctx context.Context, message Message) error {
+	payload, err := sink.Payload(message)
+	if err != nil {
+		return err
+	}
+
+	request, err := http.NewRequest(http.MethodPost, strings.TrimRight(sink.Site, "/")+"/api/v1/messages", bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	request.SetBasicAuth(sink.Email, sink.ApiKey)
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	_, err = doSinkRequest(ctx, sink.HttpClient, "Zulip", request)
+	return err
+}
+
+// Lint implements Linter
+func (sink ZulipSink) Lint(message Message) error {
+	topic := sink.Topic
+	if topic == "" {
+		topic = message.MessageTag
+	}
+
+	l := &lint{service: "Zulip"}
+	l.length("topic", topic, 60)
+	l.length("content", message.Text, 10000)
+	return l.err()
+}